@@ -11,7 +11,16 @@ import (
 )
 
 type Config struct {
-	Feeds []string
+	Feeds    []string
+	FeedMeta map[string]FeedMeta // by feed URL
+	Searches []SavedSearch
+	Settings Settings
+}
+
+// how a feed is shown in the sources view
+type FeedMeta struct {
+	Title  string // shown instead of the feed's own title, empty keeps it
+	Folder string // empty == not in a folder
 }
 
 func GetConfigDir() (string, error) {
@@ -37,9 +46,19 @@ func Load() (*Config, error) {
 		return nil, err
 	}
 
+	searches, err := loadSearches()
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := loadSettings()
+	if err != nil {
+		return nil, err
+	}
+
 	// return empty config if file not found
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return &Config{Feeds: []string{}}, nil
+		return &Config{Feeds: []string{}, FeedMeta: map[string]FeedMeta{}, Searches: searches, Settings: settings}, nil
 	}
 
 	f, err := os.Open(path)
@@ -49,17 +68,36 @@ func Load() (*Config, error) {
 	defer func() { _ = f.Close() }()
 
 	c := &Config{
-		Feeds: []string{},
+		Feeds:    []string{},
+		FeedMeta: map[string]FeedMeta{},
+		Searches: searches,
+		Settings: settings,
 	}
 
+	// one URL per line, optionally followed by a tab separated title and folder
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		l := strings.TrimSpace(scanner.Text())
 
 		// TODO: check line is a URL
 
-		if l != "" {
-			c.Feeds = append(c.Feeds, l)
+		if l == "" {
+			continue
+		}
+
+		fields := strings.Split(l, "\t")
+		url := strings.TrimSpace(fields[0])
+		c.Feeds = append(c.Feeds, url)
+
+		var meta FeedMeta
+		if len(fields) > 1 {
+			meta.Title = strings.TrimSpace(fields[1])
+		}
+		if len(fields) > 2 {
+			meta.Folder = strings.TrimSpace(fields[2])
+		}
+		if meta != (FeedMeta{}) {
+			c.FeedMeta[url] = meta
 		}
 	}
 
@@ -86,6 +124,10 @@ func (c *Config) Save() error {
 		return err
 	}
 
+	if err := c.saveSearches(); err != nil {
+		return err
+	}
+
 	// will overwrite if file exists
 	f, err := os.Create(path)
 	if err != nil {
@@ -95,7 +137,12 @@ func (c *Config) Save() error {
 
 	writer := bufio.NewWriter(f)
 	for _, feed := range c.Feeds {
-		_, err = writer.WriteString(feed + "\n")
+		line := feed
+		if meta, ok := c.FeedMeta[feed]; ok {
+			// plain URLs stay plain so the file reads as before
+			line = strings.TrimRight(feed+"\t"+meta.Title+"\t"+meta.Folder, "\t")
+		}
+		_, err = writer.WriteString(line + "\n")
 		if err != nil {
 			return err
 		}
@@ -116,8 +163,42 @@ func (c *Config) RemoveFeed(url string) error {
 	for i, feed := range c.Feeds {
 		if feed == url {
 			c.Feeds = append(c.Feeds[:i], c.Feeds[i+1:]...)
+			delete(c.FeedMeta, url)
 			return nil
 		}
 	}
 	return errors.New("feed not found")
 }
+
+// sets the title a feed is shown with, an empty title goes back to the
+// feed's own
+func (c *Config) RenameFeed(url, title string) error {
+	return c.updateFeedMeta(url, func(meta *FeedMeta) {
+		meta.Title = sanitiseField(title)
+	})
+}
+
+// moves a feed into a folder, an empty name takes it out of its folder
+func (c *Config) MoveFeed(url, folder string) error {
+	return c.updateFeedMeta(url, func(meta *FeedMeta) {
+		meta.Folder = sanitiseField(folder)
+	})
+}
+
+func (c *Config) updateFeedMeta(url string, update func(*FeedMeta)) error {
+	if !slices.Contains(c.Feeds, url) {
+		return errors.New("feed not found")
+	}
+	if c.FeedMeta == nil {
+		c.FeedMeta = make(map[string]FeedMeta)
+	}
+
+	meta := c.FeedMeta[url]
+	update(&meta)
+	if meta == (FeedMeta{}) {
+		delete(c.FeedMeta, url)
+	} else {
+		c.FeedMeta[url] = meta
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// a named search query, optionally scoped to a single feed
+type SavedSearch struct {
+	Name  string
+	Query string
+	Feed  string // feed URL, empty == all feeds
+}
+
+func GetSearchesPath() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "searches"), nil
+}
+
+// reads saved searches from disk, one tab separated "name, query, feed" per line
+func loadSearches() ([]SavedSearch, error) {
+	path, err := GetSearchesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return []SavedSearch{}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	searches := []SavedSearch{}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		l := strings.TrimSpace(scanner.Text())
+		if l == "" {
+			continue
+		}
+
+		fields := strings.Split(l, "\t")
+		if len(fields) < 2 {
+			continue
+		}
+
+		search := SavedSearch{
+			Name:  fields[0],
+			Query: fields[1],
+		}
+		if len(fields) > 2 {
+			search.Feed = fields[2]
+		}
+		searches = append(searches, search)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return searches, nil
+}
+
+func (c *Config) saveSearches() error {
+	path, err := GetSearchesPath()
+	if err != nil {
+		return err
+	}
+
+	// no point leaving an empty file around
+	if len(c.Searches) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	writer := bufio.NewWriter(f)
+	for _, s := range c.Searches {
+		_, err = writer.WriteString(s.Name + "\t" + s.Query + "\t" + s.Feed + "\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
+
+// adds a saved search, replacing any existing search with the same name
+func (c *Config) AddSearch(search SavedSearch) error {
+	search.Name = sanitiseField(search.Name)
+	search.Query = sanitiseField(search.Query)
+
+	if search.Name == "" {
+		return errors.New("search name cannot be empty")
+	}
+	if search.Query == "" {
+		return errors.New("search query cannot be empty")
+	}
+
+	for i, s := range c.Searches {
+		if s.Name == search.Name {
+			c.Searches[i] = search
+			return nil
+		}
+	}
+	c.Searches = append(c.Searches, search)
+	return nil
+}
+
+func (c *Config) RemoveSearch(name string) error {
+	for i, s := range c.Searches {
+		if s.Name == name {
+			c.Searches = append(c.Searches[:i], c.Searches[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("search not found")
+}
+
+// tabs and newlines would break the file format
+func sanitiseField(s string) string {
+	s = strings.ReplaceAll(s, "\t", " ")
+	s = strings.ReplaceAll(s, "\n", " ")
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestConfig_AddSearch(t *testing.T) {
+	existing := []SavedSearch{{Name: "go", Query: "tag:go"}}
+
+	tests := []struct {
+		name    string
+		search  SavedSearch
+		want    []SavedSearch
+		wantErr bool
+	}{
+		{
+			name:   "new",
+			search: SavedSearch{Name: "podcasts", Query: "enclosure:audio", Feed: "https://example.com/feed"},
+			want:   []SavedSearch{{Name: "go", Query: "tag:go"}, {Name: "podcasts", Query: "enclosure:audio", Feed: "https://example.com/feed"}},
+		},
+		{
+			name:   "same name replaces",
+			search: SavedSearch{Name: "go", Query: "author:rob"},
+			want:   []SavedSearch{{Name: "go", Query: "author:rob"}},
+		},
+		{
+			name:   "tabs and newlines",
+			search: SavedSearch{Name: " my\tsearch\n", Query: "a\tb"},
+			want:   []SavedSearch{{Name: "go", Query: "tag:go"}, {Name: "my search", Query: "a b"}},
+		},
+		{
+			name:    "empty name",
+			search:  SavedSearch{Name: " \t", Query: "tag:go"},
+			wantErr: true,
+		},
+		{
+			name:    "empty query",
+			search:  SavedSearch{Name: "nothing", Query: "\n"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{Searches: append([]SavedSearch(nil), existing...)}
+			err := c.AddSearch(tt.search)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddSearch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(c.Searches, tt.want) {
+				t.Errorf("Searches = %+v, want %+v", c.Searches, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_RemoveSearch(t *testing.T) {
+	tests := []struct {
+		name       string
+		searchName string
+		want       []SavedSearch
+		wantErr    bool
+	}{
+		{
+			name:       "existing",
+			searchName: "go",
+			want:       []SavedSearch{{Name: "rust", Query: "tag:rust"}},
+		},
+		{
+			name:       "missing",
+			searchName: "zig",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{Searches: []SavedSearch{{Name: "go", Query: "tag:go"}, {Name: "rust", Query: "tag:rust"}}}
+			err := c.RemoveSearch(tt.searchName)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RemoveSearch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(c.Searches, tt.want) {
+				t.Errorf("Searches = %+v, want %+v", c.Searches, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearches_roundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		searches []SavedSearch
+	}{
+		{
+			name:     "none",
+			searches: []SavedSearch{},
+		},
+		{
+			name: "with and without feed",
+			searches: []SavedSearch{
+				{Name: "go", Query: "tag:go"},
+				{Name: "podcasts", Query: "enclosure:audio", Feed: "https://example.com/feed"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			dir, err := GetConfigDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			c := &Config{Searches: tt.searches}
+			if err := c.saveSearches(); err != nil {
+				t.Fatalf("saveSearches() error = %v", err)
+			}
+			got, err := loadSearches()
+			if err != nil {
+				t.Fatalf("loadSearches() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.searches) {
+				t.Errorf("loadSearches() = %+v, want %+v", got, tt.searches)
+			}
+		})
+	}
+}
+
+func TestLoadSearches_skipsBrokenLines(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path, err := GetSearchesPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, _ := GetConfigDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("no query\n\ngo\ttag:go\t\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := loadSearches()
+	if err != nil {
+		t.Fatalf("loadSearches() error = %v", err)
+	}
+	want := []SavedSearch{{Name: "go", Query: "tag:go"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loadSearches() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// user preferences read from the settings file
+type Settings struct {
+	Prefetch            bool              // extract unread articles in the background after feeds load
+	PrefetchConcurrency int               // max articles fetched at once
+	PrefetchPerHost     int               // max articles fetched at once from a single host
+	Player              string            // command used to play podcast episodes, the URL or file is appended
+	DownloadDir         string            // where podcast episodes are downloaded to
+	Images              string            // how article images are drawn: auto, off, blocks, kitty, iterm or sixel
+	ExportDir           string            // where articles are exported to as Markdown
+	ReaderWidth         int               // preferred column width of the article reader
+	Theme               string            // colour theme: auto, a built-in theme or a file in the themes directory
+	Mouse               bool              // select, open and scroll with the mouse
+	Keys                map[string]string // keys bound to actions, "key.<action> = a, b"
+}
+
+func DefaultSettings() Settings {
+	downloadDir := "Podcasts"
+	exportDir := "Notes"
+	if home, err := os.UserHomeDir(); err == nil {
+		downloadDir = filepath.Join(home, "Podcasts")
+		exportDir = filepath.Join(home, "Notes")
+	}
+
+	return Settings{
+		Prefetch:            false,
+		PrefetchConcurrency: 4,
+		PrefetchPerHost:     1,
+		Player:              "mpv --no-video",
+		DownloadDir:         downloadDir,
+		Images:              "auto",
+		ExportDir:           exportDir,
+		ReaderWidth:         80,
+		Theme:               "auto",
+		Mouse:               true,
+		Keys:                make(map[string]string),
+	}
+}
+
+func GetSettingsPath() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "settings"), nil
+}
+
+// reads settings from disk, one "key = value" per line. lines starting with # are ignored
+func loadSettings() (Settings, error) {
+	settings := DefaultSettings()
+
+	path, err := GetSettingsPath()
+	if err != nil {
+		return settings, err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return settings, nil
+	}
+
+	if err := readKeyValues(path, settings.set); err != nil {
+		return settings, fmt.Errorf("settings %w", err)
+	}
+
+	return settings, nil
+}
+
+func (s *Settings) set(key, value string) error {
+	var err error
+
+	if action, ok := strings.CutPrefix(key, "key."); ok {
+		// actions are checked when the keymap is built
+		s.Keys[action] = value
+		return nil
+	}
+
+	switch key {
+	case "prefetch":
+		s.Prefetch, err = strconv.ParseBool(value)
+	case "prefetch_concurrency":
+		s.PrefetchConcurrency, err = parsePositiveInt(value)
+	case "prefetch_per_host":
+		s.PrefetchPerHost, err = parsePositiveInt(value)
+	case "player":
+		if value == "" {
+			return errors.New("player cannot be empty")
+		}
+		s.Player = value
+	case "download_dir":
+		s.DownloadDir, err = expandHome(value)
+	case "reader_width":
+		s.ReaderWidth, err = parsePositiveInt(value)
+	case "mouse":
+		s.Mouse, err = strconv.ParseBool(value)
+	case "theme":
+		if value == "" {
+			return errors.New("theme cannot be empty")
+		}
+		s.Theme = value
+	case "export_dir":
+		s.ExportDir, err = expandHome(value)
+	case "images":
+		switch value {
+		case "auto", "off", "blocks", "kitty", "iterm", "sixel":
+			s.Images = value
+		default:
+			return errors.New("invalid value for images: expected auto, off, blocks, kitty, iterm or sixel")
+		}
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return nil
+}
+
+func parsePositiveInt(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if n < 1 {
+		return 0, errors.New("must be at least 1")
+	}
+	return n, nil
+}
+
+// expands a leading ~ to the user's home directory
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
@@ -5,10 +5,30 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 type State struct {
-	ReadArticles map[string]bool // Key is article GUID or link
+	ReadArticles   map[string]bool      // Key is article GUID or link
+	PlayedEpisodes map[string]bool      // Key is article ID
+	Downloads      map[string]string    // article ID -> downloaded file path
+	FeedsFetched   map[string]time.Time // feed URL -> last successful fetch
+}
+
+// prefixes for episode lines in the state file. plain lines are read articles
+const (
+	playedPrefix     = "played\t"
+	downloadedPrefix = "downloaded\t"
+	fetchedPrefix    = "fetched\t"
+)
+
+func newState() *State {
+	return &State{
+		ReadArticles:   make(map[string]bool),
+		PlayedEpisodes: make(map[string]bool),
+		Downloads:      make(map[string]string),
+		FeedsFetched:   make(map[string]time.Time),
+	}
 }
 
 func GetStatePath() (string, error) {
@@ -28,7 +48,7 @@ func LoadState() (*State, error) {
 
 	// if state doesn't exist, return empty state
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return &State{ReadArticles: make(map[string]bool)}, nil
+		return newState(), nil
 	}
 
 	f, err := os.Open(path)
@@ -37,14 +57,28 @@ func LoadState() (*State, error) {
 	}
 	defer func() { _ = f.Close() }()
 
-	state := &State{
-		ReadArticles: make(map[string]bool),
-	}
+	state := newState()
 
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		l := strings.TrimSpace(scanner.Text())
-		if l != "" {
+
+		switch {
+		case l == "":
+			continue
+		case strings.HasPrefix(l, playedPrefix):
+			state.PlayedEpisodes[strings.TrimPrefix(l, playedPrefix)] = true
+		case strings.HasPrefix(l, downloadedPrefix):
+			id, path, ok := strings.Cut(strings.TrimPrefix(l, downloadedPrefix), "\t")
+			if ok {
+				state.Downloads[id] = path
+			}
+		case strings.HasPrefix(l, fetchedPrefix):
+			url, at, ok := strings.Cut(strings.TrimPrefix(l, fetchedPrefix), "\t")
+			if t, err := time.Parse(time.RFC3339, at); ok && err == nil {
+				state.FeedsFetched[url] = t
+			}
+		default:
 			state.ReadArticles[l] = true
 		}
 	}
@@ -88,6 +122,27 @@ func (s *State) Save() error {
 		}
 	}
 
+	for articleID := range s.PlayedEpisodes {
+		_, err = writer.WriteString(playedPrefix + articleID + "\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	for articleID, path := range s.Downloads {
+		_, err = writer.WriteString(downloadedPrefix + articleID + "\t" + path + "\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	for url, t := range s.FeedsFetched {
+		_, err = writer.WriteString(fetchedPrefix + url + "\t" + t.Format(time.RFC3339) + "\n")
+		if err != nil {
+			return err
+		}
+	}
+
 	return writer.Flush()
 }
 
@@ -102,3 +157,29 @@ func (s *State) UnmarkAsRead(articleID string) {
 func (s *State) IsRead(articleID string) bool {
 	return s.ReadArticles[articleID]
 }
+
+func (s *State) MarkAsPlayed(articleID string) {
+	s.PlayedEpisodes[articleID] = true
+}
+
+func (s *State) IsPlayed(articleID string) bool {
+	return s.PlayedEpisodes[articleID]
+}
+
+func (s *State) SetDownloaded(articleID, path string) {
+	s.Downloads[articleID] = path
+}
+
+// returns the local file of a downloaded episode, empty if not downloaded
+func (s *State) DownloadPath(articleID string) string {
+	return s.Downloads[articleID]
+}
+
+func (s *State) SetFetched(feedURL string, t time.Time) {
+	s.FeedsFetched[feedURL] = t
+}
+
+// returns when a feed last loaded successfully, zero if it never has
+func (s *State) LastFetched(feedURL string) time.Time {
+	return s.FeedsFetched[feedURL]
+}
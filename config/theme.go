@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// colours of the interface and of articles in the reader. colours are
+// 256-colour palette numbers or #rrggbb, "" leaves the terminal's default
+type Theme struct {
+	Name string
+
+	Active   string // selection and focus
+	Inactive string // read articles, hints, borders
+	Unread   string // unread markers
+	Title    string // article titles and view headings
+	Accent   string // feed names
+	Text     string // article descriptions
+	Status   string // status bar messages
+
+	Heading              string // h1 and h2 in articles
+	Subheading           string // h3 and below
+	Code                 string // code blocks
+	CodeBackground       string
+	InlineCode           string
+	InlineCodeBackground string
+	Footnote             string // link footnote numbers
+	Dim                  string // table borders, rules, references
+	Caption              string // image and table captions
+	Syntax               string // chroma style code is highlighted with, "" for none
+}
+
+// themes that ship with ohnurr, more can be added to the themes directory
+var builtinThemes = map[string]Theme{
+	"dark": {
+		Active: "111", Inactive: "240", Unread: "75", Title: "108", Accent: "103", Text: "252", Status: "205",
+		Heading: "147", Subheading: "111", Code: "229", CodeBackground: "235", InlineCode: "223",
+		InlineCodeBackground: "236", Footnote: "103", Dim: "240", Caption: "245", Syntax: "monokai",
+	},
+	"light": {
+		Active: "25", Inactive: "246", Unread: "27", Title: "29", Accent: "97", Text: "237", Status: "162",
+		Heading: "55", Subheading: "25", Code: "236", CodeBackground: "255", InlineCode: "130",
+		InlineCodeBackground: "254", Footnote: "97", Dim: "246", Caption: "242", Syntax: "github",
+	},
+	"high-contrast": {
+		Active: "51", Inactive: "250", Unread: "14", Title: "15", Accent: "11", Text: "15", Status: "13",
+		Heading: "15", Subheading: "14", Code: "15", CodeBackground: "0", InlineCode: "11",
+		InlineCodeBackground: "0", Footnote: "14", Dim: "250", Caption: "252", Syntax: "modus-vivendi",
+	},
+	"high-contrast-light": {
+		Active: "19", Inactive: "238", Unread: "21", Title: "0", Accent: "90", Text: "0", Status: "124",
+		Heading: "0", Subheading: "19", Code: "0", CodeBackground: "231", InlineCode: "88",
+		InlineCodeBackground: "231", Footnote: "90", Dim: "238", Caption: "236", Syntax: "modus-operandi",
+	},
+}
+
+// used whenever NO_COLOR is set, see https://no-color.org
+var noColorTheme = Theme{Name: "no-color"}
+
+func DefaultTheme() Theme {
+	t := builtinThemes["dark"]
+	t.Name = "dark"
+	return t
+}
+
+func GetThemesDir() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
+// reports whether colours are turned off with NO_COLOR
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// returns a built-in theme, or one read from the themes directory. theme
+// files are "key = value" like settings, starting from the built-in theme
+// named by "base" (dark by default) and overriding its colours
+func LoadTheme(name string) (Theme, error) {
+	if NoColor() {
+		return noColorTheme, nil
+	}
+	if t, ok := builtinThemes[name]; ok {
+		t.Name = name
+		return t, nil
+	}
+
+	dir, err := GetThemesDir()
+	if err != nil {
+		return Theme{}, err
+	}
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return Theme{}, fmt.Errorf("unknown theme %q, expected one of dark, light, high-contrast, high-contrast-light or a file in %s", name, dir)
+	}
+
+	t := DefaultTheme()
+	set := func(key, value string) error {
+		if key == "base" {
+			base, ok := builtinThemes[value]
+			if !ok {
+				return fmt.Errorf("unknown base theme %q", value)
+			}
+			t = base
+			return nil
+		}
+		return t.set(key, value)
+	}
+	if err := readKeyValues(path, set); err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", name, err)
+	}
+
+	t.Name = name
+	return t, nil
+}
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+func (t *Theme) set(key, value string) error {
+	fields := map[string]*string{
+		"active": &t.Active, "inactive": &t.Inactive, "unread": &t.Unread, "title": &t.Title,
+		"accent": &t.Accent, "text": &t.Text, "status": &t.Status,
+		"heading": &t.Heading, "subheading": &t.Subheading, "code": &t.Code,
+		"code_background": &t.CodeBackground, "inline_code": &t.InlineCode,
+		"inline_code_background": &t.InlineCodeBackground, "footnote": &t.Footnote,
+		"dim": &t.Dim, "caption": &t.Caption,
+	}
+
+	if key == "syntax" {
+		t.Syntax = value
+		return nil
+	}
+	field, ok := fields[key]
+	if !ok {
+		return fmt.Errorf("unknown colour %q", key)
+	}
+
+	if value == "none" {
+		value = ""
+	}
+	if n, err := strconv.Atoi(value); value != "" && !hexColor.MatchString(value) && (err != nil || n < 0 || n > 255) {
+		return fmt.Errorf("invalid value for %s: expected 0-255, #rrggbb or none", key)
+	}
+	*field = value
+	return nil
+}
+
+// reads a "key = value" file, one per line. blank lines and lines starting
+// with # are skipped
+func readKeyValues(path string, set func(key, value string) error) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		l := strings.TrimSpace(line)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(l, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expected key = value", i+1)
+		}
+		if err := set(strings.TrimSpace(key), strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTheme_set(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "hex",
+			key:   "active",
+			value: "#a1B2c3",
+			want:  "#a1B2c3",
+		},
+		{
+			name:  "palette number",
+			key:   "heading",
+			value: "255",
+			want:  "255",
+		},
+		{
+			name:  "palette zero",
+			key:   "heading",
+			value: "0",
+			want:  "0",
+		},
+		{
+			name:  "none",
+			key:   "code_background",
+			value: "none",
+			want:  "",
+		},
+		{
+			name:  "syntax style",
+			key:   "syntax",
+			value: "dracula",
+			want:  "dracula",
+		},
+		{
+			name:    "out of range",
+			key:     "active",
+			value:   "256",
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			key:     "active",
+			value:   "-1",
+			wantErr: true,
+		},
+		{
+			name:    "short hex",
+			key:     "active",
+			value:   "#abc",
+			wantErr: true,
+		},
+		{
+			name:    "colour name",
+			key:     "active",
+			value:   "red",
+			wantErr: true,
+		},
+		{
+			name:    "unknown key",
+			key:     "background",
+			value:   "1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			theme := DefaultTheme()
+			err := theme.set(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("set() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := map[string]string{
+				"active": theme.Active, "heading": theme.Heading,
+				"code_background": theme.CodeBackground, "syntax": theme.Syntax,
+			}[tt.key]
+			if got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   string // contents of the theme file, "" for none
+		want    func(Theme) bool
+		wantErr bool
+	}{
+		{
+			name: "builtin",
+			want: func(th Theme) bool { return th.Name == "light" && th.Active == builtinThemes["light"].Active },
+		},
+		{
+			name:  "defaults to dark",
+			theme: "# mine\nactive = 1\n",
+			want: func(th Theme) bool {
+				return th.Active == "1" && th.Title == builtinThemes["dark"].Title && th.Name == "custom"
+			},
+		},
+		{
+			name:  "base",
+			theme: "base = light\nunread = #ff0000\n",
+			want: func(th Theme) bool {
+				return th.Unread == "#ff0000" && th.Title == builtinThemes["light"].Title
+			},
+		},
+		{
+			name:    "unknown base",
+			theme:   "base = sepia\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid colour",
+			theme:   "active = 300\n",
+			wantErr: true,
+		},
+		{
+			name:    "not key value",
+			theme:   "active\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("NO_COLOR", "")
+
+			name := "light"
+			if tt.theme != "" {
+				name = "custom"
+				dir, err := GetThemesDir()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(tt.theme), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := LoadTheme(name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadTheme() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !tt.want(got) {
+				t.Errorf("LoadTheme() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestLoadTheme_unknown(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("NO_COLOR", "")
+
+	if _, err := LoadTheme("sepia"); err == nil {
+		t.Errorf("expected an error for a theme that doesn't exist")
+	}
+}
+
+func TestLoadTheme_noColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	got, err := LoadTheme("dark")
+	if err != nil {
+		t.Fatalf("LoadTheme() error = %v", err)
+	}
+	if got.Active != "" || got.Syntax != "" {
+		t.Errorf("expected no colours with NO_COLOR, got %+v", got)
+	}
+}
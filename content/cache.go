@@ -0,0 +1,344 @@
+package content
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// how much the disk caches keep. the least recently read go first
+const (
+	maxArticleCacheBytes = 64 << 20
+	maxImageCacheBytes   = 256 << 20
+)
+
+// Cache keeps recently read articles in memory and extracted articles on
+// disk, so previously read articles open instantly and offline.
+// articles are rendered lazily for each width they're shown at
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front == most recently used
+	entries  map[string]*list.Element
+	dir      string // empty == memory only
+	imageDir string
+	images   *imageStore // nil when images are shown as alt text
+
+	prefetching map[string]bool // articles being prefetched
+}
+
+// how many widths an article stays rendered at. the reader only ever needs
+// its current width, plus the previous one while a resize settles
+const renderedWidths = 2
+
+type cacheEntry struct {
+	key      string
+	baseURL  string          // what relative links are resolved against
+	html     string          // extracted article HTML
+	rendered []renderedWidth // most recent first
+	version  int             // bumped whenever what's rendered goes stale
+}
+
+type renderedWidth struct {
+	width   int
+	article *RenderedArticle
+}
+
+// returns the article rendered at a width, if it still is
+func (e *cacheEntry) renderedAt(width int) (*RenderedArticle, bool) {
+	for _, r := range e.rendered {
+		if r.width == width {
+			return r.article, true
+		}
+	}
+	return nil, false
+}
+
+// keeps an article rendered at a width, forgetting the least recent width
+// when there are too many
+func (e *cacheEntry) keepRendered(width int, article *RenderedArticle) {
+	e.rendered = append([]renderedWidth{{width, article}}, e.rendered...)
+	if len(e.rendered) > renderedWidths {
+		e.rendered = e.rendered[:renderedWidths]
+	}
+}
+
+// drops everything rendered, e.g. when the HTML or images change
+func (e *cacheEntry) forgetRendered() {
+	e.rendered = nil
+	e.version++
+}
+
+func GetCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ohnurr", "articles"), nil
+}
+
+// creates a cache holding up to capacity articles in memory.
+// the disk cache is disabled if the cache dir can't be determined,
+// and trimmed to size in the background otherwise
+func NewCache(capacity int) *Cache {
+	dir, err := GetCacheDir()
+	if err != nil {
+		dir = ""
+	}
+	c := newCache(capacity, dir)
+	if imageDir, err := GetImageCacheDir(); err == nil {
+		c.imageDir = imageDir
+	}
+
+	go func() {
+		if c.dir != "" {
+			_ = trimDir(c.dir, maxArticleCacheBytes)
+		}
+		if c.imageDir != "" {
+			_ = trimDir(c.imageDir, maxImageCacheBytes)
+		}
+	}()
+	return c
+}
+
+// sets how images in articles are drawn. images aren't downloaded when
+// they're off or there's nowhere to keep them
+func (c *Cache) SetImageMode(mode ImageMode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.images = nil
+	if mode != ImagesOff && c.imageDir != "" {
+		c.images = &imageStore{mode: mode, dir: c.imageDir}
+	}
+
+	for _, el := range c.entries {
+		el.Value.(*cacheEntry).forgetRendered()
+	}
+}
+
+func (c *Cache) currentImages() *imageStore {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.images
+}
+
+func newCache(capacity int, dir string) *Cache {
+	return &Cache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		dir:      dir,
+
+		prefetching: make(map[string]bool),
+	}
+}
+
+// reports whether an article is in memory
+func (c *Cache) Has(articleURL string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.entries[articleURL]
+	return ok
+}
+
+// returns the article rendered at the given width if it's in memory.
+// rendering happens outside the lock so other articles aren't held up
+func (c *Cache) Get(key string, width int) (*RenderedArticle, bool) {
+	c.mu.Lock()
+	el, ok := c.entries[key]
+	if !ok {
+		c.mu.Unlock()
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+
+	entry := el.Value.(*cacheEntry)
+	if rendered, ok := entry.renderedAt(width); ok {
+		c.mu.Unlock()
+		return rendered, true
+	}
+	articleHTML, baseURL, images, version := entry.html, entry.baseURL, c.images, entry.version
+	c.mu.Unlock()
+
+	rendered, err := renderArticle(articleHTML, baseURL, width, images)
+	if err != nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// only keep it if the article didn't change while it was rendering
+	if entry.version == version {
+		entry.keepRendered(width, rendered)
+	}
+	return rendered, true
+}
+
+// returns the extracted HTML of an article if it's in memory
+func (c *Cache) HTML(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	return el.Value.(*cacheEntry).html, true
+}
+
+func (c *Cache) put(key, baseURL, articleHTML string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.baseURL = baseURL
+		entry.html = articleHTML
+		entry.forgetRendered()
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{
+		key:     key,
+		baseURL: baseURL,
+		html:    articleHTML,
+	})
+
+	// evict least recently used
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// makes sure an article is in memory, checking disk, then the network
+func (c *Cache) Load(articleURL string) error {
+	if c.Has(articleURL) {
+		return nil
+	}
+
+	articleHTML, ok := c.readDisk(articleURL)
+	if !ok {
+		var err error
+		articleHTML, err = ExtractArticle(articleURL)
+		if err != nil {
+			return err
+		}
+		// disk cache is best effort
+		_ = c.writeDisk(articleURL, articleHTML)
+	}
+
+	c.put(articleURL, articleURL, articleHTML)
+	return nil
+}
+
+// keeps article HTML that's already at hand (e.g. from the feed itself)
+// in memory under the given key. relative links are resolved against baseURL
+func (c *Cache) Add(key, baseURL, articleHTML string) {
+	if c.Has(key) {
+		return
+	}
+	c.put(key, baseURL, articleHTML)
+}
+
+// downloads the images of an article in memory that aren't on disk yet.
+// articles are shown with alt text until then, so it reports whether any
+// were downloaded and the article renders differently now
+func (c *Cache) FetchImages(key string) bool {
+	c.mu.Lock()
+	el, ok := c.entries[key]
+	images := c.images
+	if !ok || images == nil {
+		c.mu.Unlock()
+		return false
+	}
+	entry := el.Value.(*cacheEntry)
+	articleHTML, baseURL := entry.html, entry.baseURL
+	c.mu.Unlock()
+
+	if images.fetch(articleHTML, baseURL) == 0 {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.forgetRendered()
+	return true
+}
+
+func (c *Cache) diskPath(articleURL string) string {
+	sum := sha256.Sum256([]byte(articleURL))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".html")
+}
+
+func (c *Cache) readDisk(articleURL string) (string, bool) {
+	if c.dir == "" {
+		return "", false
+	}
+	path := c.diskPath(articleURL)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	touch(path)
+	return string(data), true
+}
+
+func (c *Cache) writeDisk(articleURL, articleHTML string) error {
+	if c.dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.diskPath(articleURL), []byte(articleHTML), 0644)
+}
+
+// marks a cached file as just read, so trimDir keeps it longer
+func touch(path string) {
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+}
+
+// removes the least recently modified files of a cache dir until what's
+// left fits in maxBytes
+func trimDir(dir string, maxBytes int64) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var files []os.FileInfo
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		if info, err := e.Info(); err == nil {
+			files = append(files, info)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().After(files[j].ModTime())
+	})
+
+	var total int64
+	for _, f := range files {
+		total += f.Size()
+		if total > maxBytes {
+			_ = os.Remove(filepath.Join(dir, f.Name()))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package content
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_evictsLeastRecentlyUsed(t *testing.T) {
+	c := newCache(2, "")
+
+	c.put("a", "", "<p>article a</p>")
+	c.put("b", "", "<p>article b</p>")
+
+	// touch a so b becomes the oldest
+	if _, ok := c.Get("a", 80); !ok {
+		t.Fatalf("expected a to be cached")
+	}
+
+	c.put("c", "", "<p>article c</p>")
+
+	if c.Has("b") {
+		t.Errorf("expected b to be evicted")
+	}
+	if got, ok := c.Get("a", 80); !ok || !strings.HasPrefix(got.Text, "article a") {
+		t.Errorf("Get(a) = %v, %v, want %q, true", got, ok, "article a")
+	}
+	if got, ok := c.Get("c", 80); !ok || !strings.HasPrefix(got.Text, "article c") {
+		t.Errorf("Get(c) = %v, %v, want %q, true", got, ok, "article c")
+	}
+}
+
+func TestCache_diskRoundTrip(t *testing.T) {
+	c := newCache(1, t.TempDir())
+
+	if _, ok := c.readDisk("https://example.com/post"); ok {
+		t.Fatalf("expected empty disk cache")
+	}
+
+	if err := c.writeDisk("https://example.com/post", "<p>hello</p>"); err != nil {
+		t.Fatalf("writeDisk() error = %v", err)
+	}
+
+	got, ok := c.readDisk("https://example.com/post")
+	if !ok || got != "<p>hello</p>" {
+		t.Errorf("readDisk() = %q, %v, want %q, true", got, ok, "<p>hello</p>")
+	}
+}
+
+func TestCache_keepsRecentWidths(t *testing.T) {
+	c := newCache(1, "")
+	c.put("a", "", "<p>article a</p>")
+
+	for _, width := range []int{80, 60, 100} {
+		if _, ok := c.Get("a", width); !ok {
+			t.Fatalf("Get(a, %d) failed", width)
+		}
+	}
+
+	entry := c.entries["a"].Value.(*cacheEntry)
+	if len(entry.rendered) != renderedWidths {
+		t.Fatalf("kept %d widths, want %d", len(entry.rendered), renderedWidths)
+	}
+	if _, ok := entry.renderedAt(80); ok {
+		t.Errorf("expected the least recent width to be forgotten")
+	}
+	if _, ok := entry.renderedAt(100); !ok {
+		t.Errorf("expected the latest width to be kept")
+	}
+}
+
+func TestTrimDir(t *testing.T) {
+	dir := t.TempDir()
+
+	// a is the oldest, c the newest
+	now := time.Now()
+	for i, name := range []string{"a", "b", "c"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, 10), 0644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := now.Add(time.Duration(i-3) * time.Hour)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := trimDir(dir, 25); err != nil {
+		t.Fatalf("trimDir() error = %v", err)
+	}
+
+	for name, want := range map[string]bool{"a": false, "b": true, "c": true} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if got := err == nil; got != want {
+			t.Errorf("%s kept = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestTrimDir_missing(t *testing.T) {
+	if err := trimDir(filepath.Join(t.TempDir(), "missing"), 0); err != nil {
+		t.Errorf("trimDir() error = %v, want nil", err)
+	}
+}
+
+func TestCache_Get_concurrent(t *testing.T) {
+	c := newCache(2, "")
+	c.put("a", "", "<p>article a</p>")
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, ok := c.Get("a", 40+i%3*20); !ok || !strings.HasPrefix(got.Text, "article a") {
+				t.Errorf("Get(a) = %v, %v, want %q, true", got, ok, "article a")
+			}
+			c.put("b", "", "<p>article b</p>")
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package content
+
+// returns the size of a terminal cell in pixels, if the terminal reports it
+func terminalCellSize() (int, int, bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package content
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// returns the size of a terminal cell in pixels, if the terminal reports it
+func terminalCellSize() (int, int, bool) {
+	ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 || ws.Xpixel == 0 || ws.Ypixel == 0 {
+		return 0, 0, false
+	}
+	return int(ws.Xpixel / ws.Col), int(ws.Ypixel / ws.Row), true
+}
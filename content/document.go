@@ -0,0 +1,350 @@
+package content
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Document is an extracted article broken down into blocks of text,
+// independent of how it's displayed. renderers turn it into terminal
+// text, Markdown, HTML and so on
+type Document struct {
+	Blocks []Block
+	Links  []Link // Inline.Link n is Links[n-1]
+}
+
+type Link struct {
+	Text string
+	URL  string
+}
+
+type BlockKind int
+
+const (
+	ParagraphBlock   BlockKind = iota // Inline
+	HeadingBlock                      // Level, Inline
+	CodeBlock                         // Code, Language
+	ListBlock                         // Ordered, Start, Children of ItemBlock
+	ItemBlock                         // Children
+	QuoteBlock                        // Children
+	TableBlock                        // Inline caption, Children of RowBlock
+	RowBlock                          // Header, Children of CellBlock
+	CellBlock                         // Inline
+	DefinitionsBlock                  // Children of TermBlock and DescriptionBlock
+	TermBlock                         // Inline
+	DescriptionBlock                  // Children
+	CaptionBlock                      // Inline
+	RuleBlock
+	ImageBlock // Image, Inline holds the image as text for renderers that can't draw it
+)
+
+type Block struct {
+	Kind     BlockKind
+	Inline   []Inline
+	Children []Block
+	Level    int    // headings, 1-6
+	Code     string // code blocks
+	Language string // code blocks, "" when untagged
+	Ordered  bool   // lists
+	Start    int    // number of the first item of ordered lists
+	Header   bool   // table rows of column headings
+	Image    *Image
+}
+
+// inline formatting, combined as bit flags
+type InlineStyle uint8
+
+const (
+	Bold InlineStyle = 1 << iota
+	Italic
+	Strikethrough
+	Underline
+	Code
+)
+
+// a run of text sharing the same formatting
+type Inline struct {
+	Text  string // "\n" is a hard line break
+	Style InlineStyle
+	Link  int    // footnote number of the link the text is in, 0 == none
+	Image *Image // an image within text, Text is empty
+}
+
+type Image struct {
+	URL string // absolute, "" when there's nothing worth fetching
+	Alt string
+}
+
+// parses extracted article HTML into a document. links and images are
+// resolved against baseURL
+func ParseDocument(articleHTML, baseURL string) (*Document, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	p := &parser{linkIndex: make(map[string]int)}
+	p.base, _ = url.Parse(baseURL)
+
+	blocks := p.flow(doc.Find("body"))
+	return &Document{Blocks: blocks, Links: p.links}, nil
+}
+
+type parser struct {
+	base      *url.URL
+	links     []Link
+	linkIndex map[string]int // URL -> footnote number
+}
+
+// elements laid out as blocks, everything else flows inline
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"center": true, "dd": true, "details": true, "div": true, "dl": true,
+	"dt": true, "figcaption": true, "figure": true, "footer": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "summary": true,
+	"table": true, "ul": true,
+}
+
+// returns the blocks of a container, grouping runs of inline content into
+// paragraphs. containers like <div> are flattened into the flow
+func (p *parser) flow(s *goquery.Selection) []Block {
+	var blocks []Block
+	var inline []Inline
+
+	flush := func() {
+		if b, ok := paragraph(inline); ok {
+			blocks = append(blocks, b)
+		}
+		inline = nil
+	}
+
+	s.Contents().Each(func(i int, child *goquery.Selection) {
+		if !blockElements[goquery.NodeName(child)] {
+			p.inline(child, 0, 0, &inline)
+			return
+		}
+		flush()
+		blocks = append(blocks, p.block(child)...)
+	})
+	flush()
+
+	return blocks
+}
+
+// makes a paragraph of inline content, an image on its own is an image block
+func paragraph(inline []Inline) (Block, bool) {
+	var image *Image
+	images, text := 0, false
+	for _, in := range inline {
+		if in.Image != nil {
+			image = in.Image
+			images++
+		} else if strings.TrimSpace(in.Text) != "" {
+			text = true
+		}
+	}
+
+	switch {
+	case images == 1 && !text:
+		return Block{Kind: ImageBlock, Image: image, Inline: inline}, true
+	case images == 0 && !text:
+		return Block{}, false
+	}
+	return Block{Kind: ParagraphBlock, Inline: inline}, true
+}
+
+func (p *parser) block(s *goquery.Selection) []Block {
+	name := goquery.NodeName(s)
+
+	switch name {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		inline := p.inlines(s)
+		if !hasText(inline) {
+			return nil
+		}
+		return []Block{{Kind: HeadingBlock, Level: int(name[1] - '0'), Inline: inline}}
+
+	case "pre":
+		code := strings.TrimRight(s.Text(), "\n")
+		if strings.TrimSpace(code) == "" {
+			return nil
+		}
+		return []Block{{Kind: CodeBlock, Code: code, Language: codeLanguage(s)}}
+
+	case "ul", "ol":
+		list := Block{Kind: ListBlock, Ordered: name == "ol", Start: 1}
+		if v, ok := s.Attr("start"); ok {
+			if n, err := strconv.Atoi(v); err == nil {
+				list.Start = n
+			}
+		}
+		s.ChildrenFiltered("li").Each(func(i int, li *goquery.Selection) {
+			list.Children = append(list.Children, Block{Kind: ItemBlock, Children: p.flow(li)})
+		})
+		return []Block{list}
+
+	case "blockquote":
+		return []Block{{Kind: QuoteBlock, Children: p.flow(s)}}
+
+	case "table":
+		if table, ok := p.table(s); ok {
+			return []Block{table}
+		}
+		return nil
+
+	case "dl":
+		dl := Block{Kind: DefinitionsBlock}
+		s.Children().Each(func(i int, child *goquery.Selection) {
+			switch goquery.NodeName(child) {
+			case "dt":
+				dl.Children = append(dl.Children, Block{Kind: TermBlock, Inline: p.inlines(child)})
+			case "dd":
+				dl.Children = append(dl.Children, Block{Kind: DescriptionBlock, Children: p.flow(child)})
+			}
+		})
+		return []Block{dl}
+
+	case "figcaption":
+		inline := p.inlines(s)
+		if !hasText(inline) {
+			return nil
+		}
+		return []Block{{Kind: CaptionBlock, Inline: inline}}
+
+	case "hr":
+		return []Block{{Kind: RuleBlock}}
+
+	default:
+		return p.flow(s)
+	}
+}
+
+// collects rows from the table, treating <th> only rows as headers
+func (p *parser) table(s *goquery.Selection) (Block, bool) {
+	table := Block{Kind: TableBlock, Inline: p.inlines(s.ChildrenFiltered("caption"))}
+
+	// nested tables are flattened into their cell
+	s.Find("tr").Each(func(i int, tr *goquery.Selection) {
+		if tr.ParentsFiltered("table").First().Get(0) != s.Get(0) {
+			return
+		}
+
+		row := Block{Kind: RowBlock, Header: true}
+		tr.ChildrenFiltered("th, td").Each(func(j int, cell *goquery.Selection) {
+			if goquery.NodeName(cell) == "td" {
+				row.Header = false
+			}
+			row.Children = append(row.Children, Block{Kind: CellBlock, Inline: p.inlines(cell)})
+		})
+
+		if tr.ParentsFiltered("thead").Length() > 0 {
+			row.Header = true
+		}
+		if len(row.Children) > 0 {
+			table.Children = append(table.Children, row)
+		}
+	})
+
+	return table, len(table.Children) > 0
+}
+
+// returns the inline content of an element, block children included
+func (p *parser) inlines(s *goquery.Selection) []Inline {
+	var inline []Inline
+	s.Contents().Each(func(i int, child *goquery.Selection) {
+		p.inline(child, 0, 0, &inline)
+	})
+	return inline
+}
+
+func (p *parser) inline(s *goquery.Selection, style InlineStyle, link int, inline *[]Inline) {
+	children := func(style InlineStyle, link int) {
+		s.Contents().Each(func(i int, child *goquery.Selection) {
+			p.inline(child, style, link, inline)
+		})
+	}
+
+	switch goquery.NodeName(s) {
+	case "#text":
+		*inline = append(*inline, Inline{Text: s.Text(), Style: style, Link: link})
+	case "br":
+		*inline = append(*inline, Inline{Text: "\n", Style: style, Link: link})
+	case "b", "strong":
+		children(style|Bold, link)
+	case "i", "em", "cite", "var", "dfn":
+		children(style|Italic, link)
+	case "s", "strike", "del":
+		children(style|Strikethrough, link)
+	case "u", "ins":
+		children(style|Underline, link)
+	case "code", "kbd", "samp", "tt":
+		children(style|Code, link)
+	case "a":
+		if n := p.addLink(s); n != 0 {
+			link = n
+		}
+		children(style, link)
+	case "img":
+		image := &Image{
+			URL: resolveImage(s, p.base),
+			Alt: strings.TrimSpace(s.AttrOr("alt", "")),
+		}
+		if image.URL != "" || image.Alt != "" {
+			*inline = append(*inline, Inline{Style: style, Link: link, Image: image})
+		}
+	case "script", "style", "#comment":
+		// not content
+	default:
+		children(style, link)
+	}
+}
+
+// registers a link and returns its footnote number, 0 if it isn't worth one
+func (p *parser) addLink(a *goquery.Selection) int {
+	href, ok := a.Attr("href")
+	href = strings.TrimSpace(href)
+	if !ok || href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") {
+		return 0
+	}
+
+	u, err := url.Parse(href)
+	if err != nil {
+		return 0
+	}
+	if p.base != nil {
+		u = p.base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "mailto" {
+		return 0
+	}
+
+	link := u.String()
+	if n, ok := p.linkIndex[link]; ok {
+		return n
+	}
+
+	text := strings.Join(strings.Fields(a.Text()), " ")
+	if text == "" {
+		text = link
+	}
+
+	p.links = append(p.links, Link{Text: text, URL: link})
+	p.linkIndex[link] = len(p.links)
+	return len(p.links)
+}
+
+// reports whether inline content has anything besides whitespace
+func hasText(inline []Inline) bool {
+	for _, in := range inline {
+		if in.Image != nil || strings.TrimSpace(in.Text) != "" {
+			return true
+		}
+	}
+	return false
+}
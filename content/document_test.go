@@ -0,0 +1,121 @@
+package content
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDocument(t *testing.T) {
+	html := `<div><h2>Title</h2><p>Read <a href="/docs"><b>the</b> docs</a>.</p>
+		<p><img src="/a.png" alt="chart"></p>
+		<ul><li>one</li></ul></div>`
+
+	doc, err := ParseDocument(html, "https://example.com/post")
+	if err != nil {
+		t.Fatalf("ParseDocument() error = %v", err)
+	}
+
+	kinds := []BlockKind{HeadingBlock, ParagraphBlock, ImageBlock, ListBlock}
+	if len(doc.Blocks) != len(kinds) {
+		t.Fatalf("ParseDocument() got %d blocks, want %d: %+v", len(doc.Blocks), len(kinds), doc.Blocks)
+	}
+	for i, kind := range kinds {
+		if doc.Blocks[i].Kind != kind {
+			t.Errorf("block %d kind = %v, want %v", i, doc.Blocks[i].Kind, kind)
+		}
+	}
+
+	want := []Inline{
+		{Text: "Read "},
+		{Text: "the", Style: Bold, Link: 1},
+		{Text: " docs", Link: 1},
+		{Text: "."},
+	}
+	got := doc.Blocks[1].Inline
+	if len(got) != len(want) {
+		t.Fatalf("paragraph inline = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("inline %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if image := doc.Blocks[2].Image; image == nil || image.URL != "https://example.com/a.png" || image.Alt != "chart" {
+		t.Errorf("image = %+v, want https://example.com/a.png with alt text", image)
+	}
+	if len(doc.Links) != 1 || doc.Links[0].URL != "https://example.com/docs" {
+		t.Errorf("links = %v, want https://example.com/docs", doc.Links)
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	doc, err := ParseDocument(`<p>Some <em>styled</em> <a href="https://example.com/">text</a></p><pre>x &lt; y</pre>`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		renderer Renderer
+		want     string
+	}{
+		{
+			name:     "plain",
+			renderer: PlainRenderer{Width: 80},
+			want:     "Some styled text[1]\n\n\n  x < y\n\n\nLinks\n\n[1] https://example.com/",
+		},
+		{
+			name:     "markdown",
+			renderer: MarkdownRenderer{},
+			want:     "Some *styled* [text](https://example.com/)\n\n```\nx < y\n```",
+		},
+		{
+			name:     "html",
+			renderer: HTMLRenderer{},
+			want:     "<p>Some <em>styled</em> <a href=\"https://example.com/\">text</a></p>\n<pre><code>x &lt; y</code></pre>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.renderer.Render(doc)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderArticle_deeplyNested(t *testing.T) {
+	nest := func(open, close, inner string, depth int) string {
+		return strings.Repeat(open, depth) + inner + strings.Repeat(close, depth)
+	}
+
+	tests := []struct {
+		name string
+		html string
+	}{
+		{"rule in quotes", nest("<blockquote>", "</blockquote>", "<hr>", 11)},
+		{"rule in lists", nest("<ul><li>", "</li></ul>", "<hr>", 6)},
+		{"rule in ordered lists", nest("<ol start=\"100\"><li>", "</li></ol>", "<hr>", 6)},
+		{"rule in definitions", nest("<dl><dt>term</dt><dd>", "</dd></dl>", "<hr>", 6)},
+		{"text in quotes", nest("<blockquote>", "</blockquote>", "<p>some words to wrap</p>", 12)},
+		{"code in quotes", nest("<blockquote>", "</blockquote>", "<pre>x := 1</pre>", 12)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderArticle(tt.html, "", 20)
+			if err != nil {
+				t.Fatalf("RenderArticle() error = %v", err)
+			}
+			if got.Text == "" {
+				t.Errorf("RenderArticle() rendered nothing")
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package content
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// describes an exported article in the front matter of the Markdown file
+type Metadata struct {
+	Title   string
+	Authors []string
+	Feed    string
+	URL     string
+	Date    time.Time
+}
+
+// converts an article to a Markdown document with YAML front matter,
+// as used by note taking apps like Obsidian and Logseq
+func ExportMarkdown(meta Metadata, articleHTML string) (string, error) {
+	body, err := RenderMarkdown(articleHTML, meta.URL)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	if meta.Title != "" {
+		fmt.Fprintf(&b, "title: %s\n", strconv.Quote(meta.Title))
+	}
+	if len(meta.Authors) > 0 {
+		b.WriteString("author:\n")
+		for _, author := range meta.Authors {
+			fmt.Fprintf(&b, "  - %s\n", strconv.Quote(author))
+		}
+	}
+	if meta.Feed != "" {
+		fmt.Fprintf(&b, "feed: %s\n", strconv.Quote(meta.Feed))
+	}
+	if meta.URL != "" {
+		fmt.Fprintf(&b, "url: %s\n", strconv.Quote(meta.URL))
+	}
+	if !meta.Date.IsZero() {
+		fmt.Fprintf(&b, "date: %s\n", meta.Date.Format(time.RFC3339))
+	}
+	b.WriteString("---\n\n")
+
+	if meta.Title != "" {
+		b.WriteString("# " + escapeMarkdown(meta.Title) + "\n\n")
+	}
+	b.WriteString(body)
+	b.WriteString("\n")
+	return b.String(), nil
+}
+
+// writes an article as Markdown into dir, named after its title.
+// returns the path of the new file
+func SaveMarkdown(dir string, meta Metadata, articleHTML string) (string, error) {
+	doc, err := ExportMarkdown(meta, articleHTML)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	// don't overwrite earlier exports of articles with the same title
+	name := exportFileName(meta.Title)
+	path := filepath.Join(dir, name+".md")
+	for i := 2; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%s (%d).md", name, i))
+	}
+
+	if err := os.WriteFile(path, []byte(doc), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// makes a title safe to use as a file name
+func exportFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|', '#', '^', '[', ']':
+			return '-'
+		}
+		if r < 32 {
+			return -1
+		}
+		return r
+	}, title)
+
+	// keep well under file system name limits
+	if runes := []rune(name); len(runes) > 100 {
+		name = string(runes[:100])
+	}
+
+	name = strings.TrimSpace(name)
+	// note apps hide files starting with a dot
+	name = strings.TrimLeft(name, ".")
+	if name == "" {
+		return "untitled"
+	}
+	return name
+}
@@ -0,0 +1,247 @@
+package content
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/png"
+	"strings"
+
+	lg "github.com/charmbracelet/lipgloss"
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	// assumed size of a terminal cell in pixels when the terminal doesn't say
+	defaultCellWidth  = 8
+	defaultCellHeight = 16
+
+	// tallest an image gets, so it doesn't push the article off screen
+	maxImageRows = 24
+
+	// images smaller than this are icons and spacers, not worth drawing
+	minImagePixels = 16
+)
+
+// an image drawn with a graphics protocol. the escape sequence is written
+// on the first line of the image and the rest of its rows are left blank
+type graphic struct {
+	escape string
+	rows   int
+}
+
+// draws an image block, nil if images are off or it hasn't been downloaded
+func (r *renderer) renderImage(b Block, width int) []string {
+	if r.images == nil || b.Image == nil || b.Image.URL == "" {
+		return nil
+	}
+	src := b.Image.URL
+	decoded, ok := r.images.load(src)
+	if !ok {
+		return nil
+	}
+
+	cellWidth, cellHeight := cellSize()
+	cols, rows := imageCells(decoded.Bounds(), width, cellWidth, cellHeight)
+	if cols == 0 {
+		return nil
+	}
+
+	var escape string
+	switch r.images.mode {
+	case ImagesKitty:
+		escape = kittyImage(decoded, cols, rows, cellWidth, cellHeight, imageID(src))
+	case ImagesITerm:
+		escape = iTermImage(decoded, cols, rows, cellWidth, cellHeight)
+	case ImagesSixel:
+		escape = sixelImage(decoded, cols, rows, cellWidth, cellHeight)
+	default:
+		return blockImage(decoded, cols, rows)
+	}
+
+	// reserve the cells the image covers
+	lines := make([]string, rows)
+	blank := strings.Repeat(" ", cols)
+	for i := range lines {
+		lines[i] = blank
+	}
+	lines[0] = escape + blank
+	r.graphics = append(r.graphics, graphic{escape: escape, rows: rows})
+	return lines
+}
+
+// returns the size in cells to draw an image at, fitting the width and
+// never scaling it up. 0, 0 for images too small to bother with
+func imageCells(bounds image.Rectangle, width, cellWidth, cellHeight int) (cols, rows int) {
+	w, h := bounds.Dx(), bounds.Dy()
+	if w < minImagePixels || h < minImagePixels || width < 1 {
+		return 0, 0
+	}
+
+	cols = min(width, (w+cellWidth-1)/cellWidth)
+	rows = max(cols*cellWidth*h/(w*cellHeight), 1)
+	if rows > maxImageRows {
+		rows = maxImageRows
+		cols = max(rows*cellHeight*w/(h*cellWidth), 1)
+	}
+	return cols, rows
+}
+
+func cellSize() (int, int) {
+	if w, h, ok := terminalCellSize(); ok {
+		return w, h
+	}
+	return defaultCellWidth, defaultCellHeight
+}
+
+func scaleImage(img image.Image, w, h int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	xdraw.ApproxBiLinear.Scale(dst, dst.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+	return dst
+}
+
+// draws an image with "▀" half blocks, the foreground colouring the top
+// pixel of each cell and the background the bottom one
+func blockImage(img image.Image, cols, rows int) []string {
+	scaled := scaleImage(img, cols, rows*2)
+	hex := func(c color.RGBA) lg.Color {
+		return lg.Color(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+	}
+
+	lines := make([]string, rows)
+	for y := range rows {
+		var b strings.Builder
+		for x := range cols {
+			top, bottom := scaled.RGBAAt(x, 2*y), scaled.RGBAAt(x, 2*y+1)
+			topShown, bottomShown := top.A >= 128, bottom.A >= 128
+
+			// transparent halves are left to the terminal background
+			switch {
+			case topShown && bottomShown:
+				b.WriteString(lg.NewStyle().Foreground(hex(top)).Background(hex(bottom)).Render("▀"))
+			case topShown:
+				b.WriteString(lg.NewStyle().Foreground(hex(top)).Render("▀"))
+			case bottomShown:
+				b.WriteString(lg.NewStyle().Foreground(hex(bottom)).Render("▄"))
+			default:
+				b.WriteString(" ")
+			}
+		}
+		lines[y] = b.String()
+	}
+	return lines
+}
+
+// kitty image ids are per terminal, derived from the URL so redrawing an
+// image replaces it instead of stacking copies
+func imageID(src string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(src))
+	return max(h.Sum32()&0xffffff, 1)
+}
+
+func encodePNG(img image.Image) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// kitty graphics protocol, sent in chunks. C=1 keeps the cursor where it is
+func kittyImage(img image.Image, cols, rows, cellWidth, cellHeight int, id uint32) string {
+	data := encodePNG(scaleImage(img, cols*cellWidth, rows*cellHeight))
+
+	const chunkSize = 4096
+	var b strings.Builder
+	for i := 0; i < len(data); i += chunkSize {
+		chunk := data[i:min(i+chunkSize, len(data))]
+		more := 0
+		if i+chunkSize < len(data) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,i=%d,p=1,c=%d,r=%d,m=%d;%s\x1b\\", id, cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.String()
+}
+
+// iTerm2 inline image, the cursor is saved and restored around it
+// since the terminal moves it past the image
+func iTermImage(img image.Image, cols, rows, cellWidth, cellHeight int) string {
+	data := encodePNG(scaleImage(img, cols*cellWidth, rows*cellHeight))
+	return fmt.Sprintf("\x1b7\x1b]1337;File=inline=1;width=%d;height=%d;preserveAspectRatio=0:%s\a\x1b8", cols, rows, data)
+}
+
+// sixel image with a 216 colour palette, unset pixels keep the background
+func sixelImage(img image.Image, cols, rows, cellWidth, cellHeight int) string {
+	w, h := cols*cellWidth, rows*cellHeight
+	scaled := scaleImage(img, w, h)
+
+	// index 0 is transparent
+	colours := append(color.Palette{color.Transparent}, palette.WebSafe...)
+	paletted := image.NewPaletted(scaled.Bounds(), colours)
+	xdraw.FloydSteinberg.Draw(paletted, paletted.Bounds(), scaled, image.Point{})
+
+	var b strings.Builder
+	b.WriteString("\x1b7\x1bP0;1;0q")
+	fmt.Fprintf(&b, "\"1;1;%d;%d", w, h)
+	for i, c := range colours[1:] {
+		cr, cg, cb, _ := c.RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i+1, cr*100/0xffff, cg*100/0xffff, cb*100/0xffff)
+	}
+
+	// each sixel character is a column of 6 pixels, drawn a colour at a time
+	for top := 0; top < h; top += 6 {
+		var used [256]bool
+		for y := top; y < min(top+6, h); y++ {
+			for _, idx := range paletted.Pix[y*paletted.Stride : y*paletted.Stride+w] {
+				used[idx] = true
+			}
+		}
+
+		for idx := 1; idx < len(colours); idx++ {
+			if !used[idx] {
+				continue
+			}
+			fmt.Fprintf(&b, "#%d", idx)
+
+			run, last := 0, byte(0)
+			flush := func() {
+				switch {
+				case run > 3:
+					fmt.Fprintf(&b, "!%d%c", run, last)
+				case run > 0:
+					b.WriteString(strings.Repeat(string(last), run))
+				}
+			}
+			for x := range w {
+				bits := 0
+				for dy := range 6 {
+					if y := top + dy; y < h && int(paletted.Pix[y*paletted.Stride+x]) == idx {
+						bits |= 1 << dy
+					}
+				}
+				ch := byte(63 + bits)
+				if ch != last {
+					flush()
+					run, last = 0, ch
+				}
+				run++
+			}
+			flush()
+			b.WriteByte('$')
+		}
+		b.WriteByte('-')
+	}
+
+	b.WriteString("\x1b\\\x1b8")
+	return b.String()
+}
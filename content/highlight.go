@@ -0,0 +1,174 @@
+package content
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// code blocks are highlighted with this chroma style from the theme, drawn
+// on the background of codeBlockStyle. nil leaves code plain
+var codeTheme *chroma.Style
+
+// horizontal and vertical padding around code, same as codeBlockStyle
+const (
+	codePadX = 2
+	codePadY = 1
+)
+
+// class prefixes used to tag code with its language, e.g.
+// class="language-go" (markdown renderers), "lang-go", "highlight-source-go" (github)
+var languageClassPrefixes = []string{"language-", "lang-", "highlight-source-"}
+
+// returns the language a <pre> block is tagged with, "" if it isn't
+func codeLanguage(pre *goquery.Selection) string {
+	for _, s := range []*goquery.Selection{pre.ChildrenFiltered("code").First(), pre, pre.Parent()} {
+		if s.Length() == 0 {
+			continue
+		}
+		for _, attr := range []string{"data-lang", "data-language", "lang"} {
+			// lang on the wrapper is usually a natural language, e.g. "en"
+			if attr == "lang" && s != pre && goquery.NodeName(s) != "code" {
+				continue
+			}
+			if v, ok := s.Attr(attr); ok && strings.TrimSpace(v) != "" {
+				return strings.ToLower(strings.TrimSpace(v))
+			}
+		}
+		class, _ := s.Attr("class")
+		for _, c := range strings.Fields(class) {
+			for _, prefix := range languageClassPrefixes {
+				if lang, ok := strings.CutPrefix(c, prefix); ok && lang != "" {
+					return strings.ToLower(lang)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// picks a lexer for the language, guessing from the code when the language
+// is unknown or missing
+func codeLexer(code, language string) chroma.Lexer {
+	lexer := lexers.Get(language)
+	if language == "" || lexer == nil {
+		lexer = lexers.Analyse(code)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	return chroma.Coalesce(lexer)
+}
+
+// returns the lipgloss style for a token type in the code theme
+func tokenStyle(t chroma.TokenType) lg.Style {
+	s := codeBlockStyle.UnsetPadding()
+	if codeTheme == nil {
+		return s
+	}
+	entry := codeTheme.Get(t)
+	if entry.Colour.IsSet() {
+		s = s.Foreground(lg.Color(entry.Colour.String()))
+	}
+	if entry.Bold == chroma.Yes {
+		s = s.Bold(true)
+	}
+	if entry.Italic == chroma.Yes {
+		s = s.Italic(true)
+	}
+	if entry.Underline == chroma.Yes {
+		s = s.Underline(true)
+	}
+	return s
+}
+
+// a line of code being laid out, text is buffered until the style changes
+type codeRow struct {
+	b       strings.Builder
+	width   int
+	pending strings.Builder
+	style   chroma.TokenType
+}
+
+func (r *codeRow) flush() {
+	if r.pending.Len() > 0 {
+		r.b.WriteString(tokenStyle(r.style).Render(r.pending.String()))
+		r.pending.Reset()
+	}
+}
+
+func (r *codeRow) write(t chroma.TokenType, text string, width int) {
+	if t != r.style {
+		r.flush()
+		r.style = t
+	}
+	r.pending.WriteString(text)
+	r.width += width
+}
+
+// highlights code and lays it out as a padded block at most width columns
+// wide. lines too long to fit are broken, continuing at their indentation
+func highlightCode(code, language string, width int) []string {
+	code = strings.ReplaceAll(code, "\t", "    ")
+	inner := max(width-2*codePadX, 1)
+
+	iterator, err := codeLexer(code, language).Tokenise(nil, code)
+	if err != nil {
+		return strings.Split(codeBlockStyle.Render(code), "\n")
+	}
+
+	var rows []*codeRow
+	for _, line := range chroma.SplitTokensIntoLines(iterator.Tokens()) {
+		var text strings.Builder
+		for _, tok := range line {
+			text.WriteString(tok.Value)
+		}
+		raw := strings.TrimRight(text.String(), "\n")
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+		// don't let deep indentation leave no room for the code
+		indent = min(indent, inner/2)
+
+		row := &codeRow{}
+		rows = append(rows, row)
+		for _, tok := range line {
+			for _, c := range strings.TrimRight(tok.Value, "\n") {
+				cw := lg.Width(string(c))
+				if row.width+cw > inner && row.width > indent {
+					row.flush()
+					row = &codeRow{}
+					rows = append(rows, row)
+					row.write(chroma.Text, strings.Repeat(" ", indent), indent)
+				}
+				row.write(tok.Type, string(c), cw)
+			}
+		}
+		row.flush()
+	}
+
+	// like codeBlockStyle, the block is as wide as its longest line
+	blockWidth := 0
+	for _, row := range rows {
+		blockWidth = max(blockWidth, row.width)
+	}
+
+	background := codeBlockStyle.UnsetPadding()
+	pad := func(n int) string {
+		return background.Render(strings.Repeat(" ", n))
+	}
+	blank := pad(blockWidth + 2*codePadX)
+
+	lines := make([]string, 0, len(rows)+2*codePadY)
+	for range codePadY {
+		lines = append(lines, blank)
+	}
+	for _, row := range rows {
+		lines = append(lines, pad(codePadX)+row.b.String()+pad(blockWidth-row.width+codePadX))
+	}
+	for range codePadY {
+		lines = append(lines, blank)
+	}
+	return lines
+}
@@ -0,0 +1,58 @@
+package content
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestCodeLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"class on code", `<pre><code class="hljs language-Go">x</code></pre>`, "go"},
+		{"class on pre", `<pre class="lang-python">x</pre>`, "python"},
+		{"github wrapper", `<div class="highlight highlight-source-rust"><pre>x</pre></div>`, "rust"},
+		{"data attribute", `<pre data-lang="js">x</pre>`, "js"},
+		{"lang attribute", `<pre><code lang="sh">x</code></pre>`, "sh"},
+		{"natural language on wrapper", `<div lang="en"><pre>x</pre></div>`, ""},
+		{"untagged", `<pre>x</pre>`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := codeLanguage(doc.Find("pre")); got != tt.want {
+				t.Errorf("codeLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderArticle_codeBlock(t *testing.T) {
+	html := "<pre><code class=\"language-go\">func main() {\n\tprintln(\"a long line of code\")\n}\n</code></pre>"
+
+	got, err := RenderArticle(html, "", 24)
+	if err != nil {
+		t.Fatalf("RenderArticle() error = %v", err)
+	}
+
+	// tabs are expanded and long lines continue at their indentation
+	want := []string{
+		"                        ",
+		"  func main() {         ",
+		"      println(\"a long   ",
+		"      line of code\")    ",
+		"  }                     ",
+		"                        ",
+	}
+	if got.Text != strings.Join(want, "\n") {
+		t.Errorf("RenderArticle() =\n%s\nwant\n%s", got.Text, strings.Join(want, "\n"))
+	}
+}
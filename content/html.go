@@ -0,0 +1,153 @@
+package content
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// renders documents as clean HTML, without the scripts, styles and
+// layout of the original page
+type HTMLRenderer struct{}
+
+func (HTMLRenderer) Render(doc *Document) (string, error) {
+	w := &htmlWriter{doc: doc}
+	return strings.Join(w.blocks(doc.Blocks), "\n"), nil
+}
+
+type htmlWriter struct {
+	doc *Document
+}
+
+func (w *htmlWriter) blocks(blocks []Block) []string {
+	out := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		out = append(out, w.block(b))
+	}
+	return out
+}
+
+func (w *htmlWriter) block(b Block) string {
+	switch b.Kind {
+	case HeadingBlock:
+		return fmt.Sprintf("<h%d>%s</h%d>", b.Level, w.inline(b.Inline), b.Level)
+
+	case CodeBlock:
+		class := ""
+		if b.Language != "" {
+			class = ` class="language-` + html.EscapeString(b.Language) + `"`
+		}
+		return "<pre><code" + class + ">" + html.EscapeString(b.Code) + "</code></pre>"
+
+	case ListBlock:
+		open := "<ul>"
+		if b.Ordered {
+			open = "<ol>"
+			if b.Start != 1 {
+				open = fmt.Sprintf(`<ol start="%d">`, b.Start)
+			}
+		}
+		var items []string
+		for _, item := range b.Children {
+			items = append(items, "<li>"+strings.Join(w.blocks(item.Children), "\n")+"</li>")
+		}
+		end := "</ul>"
+		if b.Ordered {
+			end = "</ol>"
+		}
+		return open + "\n" + strings.Join(items, "\n") + "\n" + end
+
+	case QuoteBlock:
+		return "<blockquote>\n" + strings.Join(w.blocks(b.Children), "\n") + "\n</blockquote>"
+
+	case TableBlock:
+		lines := []string{"<table>"}
+		if len(b.Inline) > 0 {
+			lines = append(lines, "<caption>"+w.inline(b.Inline)+"</caption>")
+		}
+		for _, row := range b.Children {
+			tag := "td"
+			if row.Header {
+				tag = "th"
+			}
+			var cells strings.Builder
+			for _, cell := range row.Children {
+				fmt.Fprintf(&cells, "<%s>%s</%s>", tag, w.inline(cell.Inline), tag)
+			}
+			lines = append(lines, "<tr>"+cells.String()+"</tr>")
+		}
+		return strings.Join(append(lines, "</table>"), "\n")
+
+	case DefinitionsBlock:
+		lines := []string{"<dl>"}
+		for _, child := range b.Children {
+			switch child.Kind {
+			case TermBlock:
+				lines = append(lines, "<dt>"+w.inline(child.Inline)+"</dt>")
+			case DescriptionBlock:
+				lines = append(lines, "<dd>"+strings.Join(w.blocks(child.Children), "\n")+"</dd>")
+			}
+		}
+		return strings.Join(append(lines, "</dl>"), "\n")
+
+	case CaptionBlock:
+		return "<p><em>" + w.inline(b.Inline) + "</em></p>"
+
+	case RuleBlock:
+		return "<hr>"
+
+	default:
+		return "<p>" + w.inline(b.Inline) + "</p>"
+	}
+}
+
+// tags for inline styles, outermost first
+var htmlStyleTags = []struct {
+	style InlineStyle
+	tag   string
+}{
+	{Bold, "strong"},
+	{Italic, "em"},
+	{Strikethrough, "del"},
+	{Underline, "u"},
+	{Code, "code"},
+}
+
+func (w *htmlWriter) inline(inline []Inline) string {
+	var b strings.Builder
+	link := 0
+	for _, in := range inline {
+		if in.Link != link {
+			if link != 0 {
+				b.WriteString("</a>")
+			}
+			if in.Link != 0 {
+				b.WriteString(`<a href="` + html.EscapeString(w.doc.Links[in.Link-1].URL) + `">`)
+			}
+			link = in.Link
+		}
+
+		switch {
+		case in.Image != nil:
+			if in.Image.URL != "" {
+				fmt.Fprintf(&b, `<img src="%s" alt="%s">`, html.EscapeString(in.Image.URL), html.EscapeString(in.Image.Alt))
+			}
+			continue
+		case in.Text == "\n":
+			b.WriteString("<br>")
+			continue
+		}
+
+		text := html.EscapeString(collapseSpace(in.Text))
+		for i := len(htmlStyleTags) - 1; i >= 0; i-- {
+			if t := htmlStyleTags[i]; in.Style&t.style != 0 {
+				text = "<" + t.tag + ">" + text + "</" + t.tag + ">"
+			}
+		}
+		b.WriteString(text)
+	}
+	if link != 0 {
+		b.WriteString("</a>")
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,263 @@
+package content
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/PuerkitoBio/goquery"
+	_ "golang.org/x/image/webp"
+)
+
+// how images are drawn in the reader
+type ImageMode int
+
+const (
+	ImagesOff    ImageMode = iota // alt text only
+	ImagesBlocks                  // unicode half blocks, works in any colour terminal
+	ImagesKitty                   // kitty graphics protocol
+	ImagesITerm                   // iTerm2 inline images, also supported by WezTerm
+	ImagesSixel                   // DEC sixel graphics
+)
+
+const (
+	// images per article worth downloading, the rest are shown as alt text
+	maxArticleImages = 20
+	maxImageBytes    = 10 << 20
+	imageFetchers    = 4
+)
+
+// parses an image mode from settings, "auto" picks what the terminal
+// supports. colour is false when colours are turned off
+func ParseImageMode(s string, colour bool) (ImageMode, error) {
+	switch s {
+	case "auto":
+		return DetectImageMode(colour), nil
+	case "off":
+		return ImagesOff, nil
+	case "blocks":
+		return ImagesBlocks, nil
+	case "kitty":
+		return ImagesKitty, nil
+	case "iterm":
+		return ImagesITerm, nil
+	case "sixel":
+		return ImagesSixel, nil
+	}
+	return ImagesOff, fmt.Errorf("unknown image mode %q", s)
+}
+
+// guesses the best image protocol from the environment. terminal
+// multiplexers hide the outer terminal, so they get half blocks, unless
+// colours are turned off
+func DetectImageMode(colour bool) ImageMode {
+	term := os.Getenv("TERM")
+	program := os.Getenv("TERM_PROGRAM")
+
+	// half blocks are drawn with colours
+	blocks := ImagesBlocks
+	if !colour {
+		blocks = ImagesOff
+	}
+
+	switch {
+	case os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen"):
+		return blocks
+	case term == "xterm-kitty" || term == "xterm-ghostty" || os.Getenv("KITTY_WINDOW_ID") != "":
+		return ImagesKitty
+	case program == "iTerm.app" || program == "WezTerm":
+		return ImagesITerm
+	case strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm") || strings.HasPrefix(term, "contour"):
+		return ImagesSixel
+	}
+	return blocks
+}
+
+// returns an escape sequence removing images drawn by a previous frame.
+// only kitty keeps images around when the text under them is redrawn
+func (m ImageMode) Clear() string {
+	if m == ImagesKitty {
+		return "\x1b_Ga=d,d=A,q=2\x1b\\"
+	}
+	return ""
+}
+
+func GetImageCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ohnurr", "images"), nil
+}
+
+// images downloaded for articles, kept on disk by URL
+type imageStore struct {
+	mode ImageMode
+	dir  string
+}
+
+func (s *imageStore) path(imageURL string) string {
+	sum := sha256.Sum256([]byte(imageURL))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:]))
+}
+
+// returns the decoded image if it has been downloaded
+func (s *imageStore) load(imageURL string) (image.Image, bool) {
+	path := s.path(imageURL)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer func() { _ = f.Close() }()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, false
+	}
+	touch(path)
+	return img, true
+}
+
+// downloads the images of an article that aren't on disk yet, returning
+// how many were. images are optional, so failures are ignored
+func (s *imageStore) fetch(articleHTML, baseURL string) int {
+	var (
+		wg         sync.WaitGroup
+		downloaded atomic.Int32
+	)
+	sem := make(chan struct{}, imageFetchers)
+
+	for _, src := range imageSources(articleHTML, baseURL) {
+		if _, err := os.Stat(s.path(src)); err == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(imageURL string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if s.download(imageURL) == nil {
+				downloaded.Add(1)
+			}
+		}(src)
+	}
+	wg.Wait()
+	return int(downloaded.Load())
+}
+
+func (s *imageStore) download(imageURL string) error {
+	req, err := http.NewRequest("GET", imageURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "ohnurr")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "image/") {
+		return errors.New("not an image")
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	// write to a temp file so a failed download doesn't leave a broken image
+	path := s.path(imageURL)
+	f, err := os.CreateTemp(s.dir, ".download-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	n, err := io.Copy(f, io.LimitReader(resp.Body, maxImageBytes+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if n > maxImageBytes {
+		return errors.New("image too large")
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// returns the absolute URLs of the images in an article, in order
+func imageSources(articleHTML, baseURL string) []string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(articleHTML))
+	if err != nil {
+		return nil
+	}
+	base, _ := url.Parse(baseURL)
+
+	var sources []string
+	seen := make(map[string]bool)
+	doc.Find("img").EachWithBreak(func(i int, img *goquery.Selection) bool {
+		src := resolveImage(img, base)
+		if src != "" && !seen[src] {
+			seen[src] = true
+			sources = append(sources, src)
+		}
+		return len(sources) < maxArticleImages
+	})
+	return sources
+}
+
+// returns the absolute URL of an <img>, "" if it has none worth fetching.
+// lazy loaded images keep the real URL in data-src
+func resolveImage(img *goquery.Selection, base *url.URL) string {
+	if isTrackingPixel(img) {
+		return ""
+	}
+
+	src, _ := img.Attr("src")
+	if lazy, ok := img.Attr("data-src"); ok && lazy != "" {
+		src = lazy
+	}
+	src = strings.TrimSpace(src)
+	if src == "" || strings.HasPrefix(src, "data:") {
+		return ""
+	}
+
+	u, err := url.Parse(src)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
+
+// reports whether an image is sized to be invisible
+func isTrackingPixel(img *goquery.Selection) bool {
+	w, _ := img.Attr("width")
+	h, _ := img.Attr("height")
+	return w == "0" || w == "1" || h == "0" || h == "1"
+}
@@ -0,0 +1,93 @@
+package content
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestImageCells(t *testing.T) {
+	tests := []struct {
+		name       string
+		w, h       int
+		width      int
+		cols, rows int
+	}{
+		{"fits at natural size", 160, 160, 80, 20, 10},
+		{"scaled to the width", 1600, 800, 80, 80, 20},
+		{"tall images are capped", 160, 1600, 80, 4, 24},
+		{"icons are skipped", 12, 12, 80, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cols, rows := imageCells(image.Rect(0, 0, tt.w, tt.h), tt.width, 8, 16)
+			if cols != tt.cols || rows != tt.rows {
+				t.Errorf("imageCells() = %d, %d, want %d, %d", cols, rows, tt.cols, tt.rows)
+			}
+		})
+	}
+}
+
+func TestRenderArticle_images(t *testing.T) {
+	store := &imageStore{mode: ImagesBlocks, dir: t.TempDir()}
+
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	for x := range 32 {
+		for y := range 32 {
+			img.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	f, err := os.Create(store.path("https://example.com/red.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	html := `<p>before</p><p><a href="/big"><img src="/red.png"></a></p><p><img src="/missing.png"><img src="/pixel.gif" width="1" height="1"></p>`
+	got, err := renderArticle(html, "https://example.com/post", 80, store)
+	if err != nil {
+		t.Fatalf("renderArticle() error = %v", err)
+	}
+
+	// 32x32 pixels at 8x16 cells is 4 columns by 2 rows of half blocks
+	block := strings.Repeat("▀", 4)
+	want := "before\n\n" + block + "\n" + block + "\n\n[Image]"
+	if got.Text != want {
+		t.Errorf("renderArticle() = %q, want %q", got.Text, want)
+	}
+}
+
+func TestCache_FetchImages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_ = png.Encode(w, image.NewRGBA(image.Rect(0, 0, 32, 32)))
+	}))
+	defer srv.Close()
+
+	c := newCache(1, "")
+	c.imageDir = t.TempDir()
+	c.SetImageMode(ImagesBlocks)
+	c.Add("post", srv.URL+"/post", `<p><img src="/a.png" alt="a"></p>`)
+
+	if got, _ := c.Get("post", 80); !strings.Contains(got.Text, "[Image: a]") {
+		t.Fatalf("expected alt text before the image is downloaded, got %q", got.Text)
+	}
+	if !c.FetchImages("post") {
+		t.Fatalf("FetchImages() = false, want true")
+	}
+	if got, _ := c.Get("post", 80); strings.Contains(got.Text, "[Image: a]") {
+		t.Errorf("expected the image once downloaded, got %q", got.Text)
+	}
+	if c.FetchImages("post") {
+		t.Errorf("FetchImages() = true for images already on disk, want false")
+	}
+}
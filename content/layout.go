@@ -0,0 +1,373 @@
+package content
+
+import (
+	"strings"
+	"unicode"
+
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// formatting only used in the terminal, on top of the document's own
+const (
+	styleFootnote InlineStyle = Code << (iota + 1)
+	styleDim
+)
+
+// a run of text sharing the same inline formatting. a span of "\n" is a hard line break
+type span struct {
+	text  string
+	style InlineStyle
+}
+
+// part of a word with a single style, "<b>foo</b>bar" is one word with two pieces
+type piece struct {
+	text  string
+	style InlineStyle
+}
+
+type word struct {
+	pieces []piece
+	space  bool // separated from the previous word by a space, false for CJK text and after hyphenation
+}
+
+// soft hyphens mark where a word may be hyphenated, they're only shown
+// when it is
+const softHyphen = "\u00ad"
+
+func (w word) width() int {
+	total := 0
+	for _, p := range w.pieces {
+		total += lg.Width(visible(p.text))
+	}
+	return total
+}
+
+func visible(text string) string {
+	return strings.ReplaceAll(text, softHyphen, "")
+}
+
+// a single character of a word with its style
+type styledRune struct {
+	r     rune
+	style InlineStyle
+}
+
+func (w word) runes() []styledRune {
+	var runes []styledRune
+	for _, p := range w.pieces {
+		for _, r := range p.text {
+			runes = append(runes, styledRune{r: r, style: p.style})
+		}
+	}
+	return runes
+}
+
+func newWord(runes []styledRune, space bool) word {
+	w := word{space: space}
+	for _, sr := range runes {
+		if n := len(w.pieces); n > 0 && w.pieces[n-1].style == sr.style {
+			w.pieces[n-1].text += string(sr.r)
+		} else {
+			w.pieces = append(w.pieces, piece{text: string(sr.r), style: sr.style})
+		}
+	}
+	return w
+}
+
+func runesWidth(runes []styledRune) int {
+	total := 0
+	for _, sr := range runes {
+		if string(sr.r) != softHyphen {
+			total += lg.Width(string(sr.r))
+		}
+	}
+	return total
+}
+
+// reports whether lines may break on either side of a character without a
+// space, as in Chinese and Japanese text
+func isWide(r rune) bool {
+	return lg.Width(string(r)) > 1
+}
+
+// returns the lipgloss style for inline formatting, layered over the
+// style of the block it's in (e.g. a heading)
+func (st InlineStyle) render(base lg.Style) lg.Style {
+	s := lg.NewStyle()
+	switch {
+	case st&Code != 0:
+		s = codeInlineStyle
+	case st&styleFootnote != 0:
+		s = footnoteStyle
+	case st&styleDim != 0:
+		s = dimTextStyle
+	}
+
+	if st&Bold != 0 {
+		s = s.Bold(true)
+	}
+	if st&Italic != 0 {
+		s = s.Italic(true)
+	}
+	if st&Strikethrough != 0 {
+		s = s.Strikethrough(true)
+	}
+	if st&Underline != 0 {
+		s = s.Underline(true)
+	}
+
+	return s.Inherit(base)
+}
+
+// splits spans into words, one slice of words per hard line break.
+// wide characters are words of their own so CJK text can wrap anywhere
+func tokenize(spans []span) [][]word {
+	lines := [][]word{nil}
+	var current word
+	space := false
+
+	flush := func() {
+		if len(current.pieces) > 0 {
+			lines[len(lines)-1] = append(lines[len(lines)-1], current)
+			current = word{}
+		}
+	}
+	add := func(text string, style InlineStyle) {
+		if len(current.pieces) == 0 {
+			current.space = space
+			space = false
+		}
+		current.pieces = append(current.pieces, piece{text: text, style: style})
+	}
+
+	for _, sp := range spans {
+		if sp.text == "\n" {
+			flush()
+			lines = append(lines, nil)
+			space = false
+			continue
+		}
+
+		start := 0
+		for i, r := range sp.text {
+			switch {
+			case unicode.IsSpace(r):
+				if start < i {
+					add(sp.text[start:i], sp.style)
+				}
+				flush()
+				space = true
+				start = i + len(string(r))
+			case isWide(r):
+				if start < i {
+					add(sp.text[start:i], sp.style)
+				}
+				flush()
+				add(string(r), sp.style)
+				flush()
+				start = i + len(string(r))
+			}
+		}
+		if start < len(sp.text) {
+			add(sp.text[start:], sp.style)
+		}
+	}
+	flush()
+
+	return lines
+}
+
+// greedily fills lines up to width display columns, hyphenating words
+// that don't fit where they have a hyphen or soft hyphen, and breaking
+// words longer than a whole line
+func wrapWords(words []word, width int) [][]word {
+	width = max(width, 1)
+	var lines [][]word
+	var current []word
+	currentWidth := 0
+
+	newLine := func() {
+		lines = append(lines, current)
+		current = nil
+		currentWidth = 0
+	}
+	add := func(w word) {
+		if len(current) > 0 && w.space {
+			currentWidth++
+		}
+		current = append(current, w)
+		currentWidth += w.width()
+	}
+
+	for _, w := range words {
+		for {
+			gap := 0
+			if len(current) > 0 && w.space {
+				gap = 1
+			}
+			if currentWidth+gap+w.width() <= width {
+				add(w)
+				break
+			}
+
+			if head, tail, ok := hyphenate(w, width-currentWidth-gap); ok {
+				add(head)
+				newLine()
+				w = tail
+				continue
+			}
+			if len(current) > 0 {
+				newLine()
+				continue
+			}
+
+			// alone on a line and still too wide
+			head, tail := breakWord(w, width)
+			add(head)
+			newLine()
+			w = tail
+		}
+	}
+
+	if len(current) > 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// splits a word at the last hyphen or soft hyphen where the first part fits
+// in available columns. both parts are kept at least two characters long
+func hyphenate(w word, available int) (head, tail word, ok bool) {
+	runes := w.runes()
+	for i := len(runes) - 3; i >= 2; i-- {
+		r := string(runes[i].r)
+		if r != "-" && r != softHyphen {
+			continue
+		}
+
+		headRunes := runes[:i+1]
+		if r == softHyphen {
+			headRunes = append(runes[:i:i], styledRune{r: '-', style: runes[i].style})
+		}
+		if runesWidth(headRunes) <= available {
+			return newWord(headRunes, w.space), newWord(runes[i+1:], false), true
+		}
+	}
+	return word{}, word{}, false
+}
+
+// force breaks a word longer than width, with a hyphen unless it looks
+// like a URL or path where a hyphen would be misleading
+func breakWord(w word, width int) (head, tail word) {
+	runes := w.runes()
+	hyphen := width > 2 && !strings.Contains(plainWord(w), "/")
+	available := width
+	if hyphen {
+		available--
+	}
+
+	n, total := 0, 0
+	for n < len(runes) {
+		rw := runesWidth(runes[n : n+1])
+		if total+rw > available && n > 0 {
+			break
+		}
+		total += rw
+		n++
+	}
+
+	headRunes := runes[:n:n]
+	if hyphen && n < len(runes) {
+		headRunes = append(headRunes, styledRune{r: '-', style: runes[n-1].style})
+	}
+	return newWord(headRunes, w.space), newWord(runes[n:], false)
+}
+
+func plainWord(w word) string {
+	var b strings.Builder
+	for _, p := range w.pieces {
+		b.WriteString(visible(p.text))
+	}
+	return b.String()
+}
+
+func renderWords(words []word, base lg.Style) string {
+	var b strings.Builder
+	for i, w := range words {
+		if i > 0 && w.space {
+			b.WriteString(" ")
+		}
+		for _, p := range w.pieces {
+			b.WriteString(p.style.render(base).Render(visible(p.text)))
+		}
+	}
+	return b.String()
+}
+
+// lays out inline content as wrapped, styled lines
+func layoutSpans(spans []span, width int, base lg.Style) []string {
+	var lines []string
+	for _, hardLine := range tokenize(spans) {
+		if len(hardLine) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		for _, wrapped := range wrapWords(hardLine, width) {
+			lines = append(lines, renderWords(wrapped, base))
+		}
+	}
+	return trimBlankLines(lines)
+}
+
+// returns the text of spans without styling, whitespace collapsed
+func plainText(spans []span) string {
+	var lines []string
+	for _, hardLine := range tokenize(spans) {
+		var b strings.Builder
+		for i, w := range hardLine {
+			if i > 0 && w.space {
+				b.WriteString(" ")
+			}
+			b.WriteString(plainWord(w))
+		}
+		lines = append(lines, b.String())
+	}
+	return strings.Join(trimBlankLines(lines), "\n")
+}
+
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// prefixes the first line with marker and indents the rest to line up with it
+func hangingIndent(lines []string, marker string) []string {
+	indent := strings.Repeat(" ", lg.Width(marker))
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		prefix := indent
+		if i == 0 {
+			prefix = marker
+		}
+		if line == "" {
+			out[i] = ""
+		} else {
+			out[i] = prefix + line
+		}
+	}
+	return out
+}
+
+// prefixes every line, e.g. with a blockquote bar
+func prefixLines(lines []string, prefix string) []string {
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = prefix + line
+	}
+	return out
+}
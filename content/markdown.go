@@ -0,0 +1,333 @@
+package content
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// renders documents as Markdown
+type MarkdownRenderer struct{}
+
+func (MarkdownRenderer) Render(doc *Document) (string, error) {
+	w := &markdownWriter{doc: doc}
+	return strings.Join(w.blocks(doc.Blocks), "\n\n"), nil
+}
+
+// converts extracted article HTML to Markdown. links and images are
+// resolved against baseURL
+func RenderMarkdown(articleHTML, baseURL string) (string, error) {
+	doc, err := ParseDocument(articleHTML, baseURL)
+	if err != nil {
+		return "", err
+	}
+	return MarkdownRenderer{}.Render(doc)
+}
+
+type markdownWriter struct {
+	doc *Document
+}
+
+func (w *markdownWriter) blocks(blocks []Block) []string {
+	var out []string
+	for _, b := range blocks {
+		if text := w.block(b); text != "" {
+			out = append(out, text)
+		}
+	}
+	return out
+}
+
+func (w *markdownWriter) block(b Block) string {
+	switch b.Kind {
+	case HeadingBlock:
+		return strings.Repeat("#", b.Level) + " " + w.inline(b.Inline)
+
+	case CodeBlock:
+		// the fence has to be longer than any run of backticks in the code
+		fence := strings.Repeat("`", max(longestRun(b.Code, '`')+1, 3))
+		return fence + b.Language + "\n" + b.Code + "\n" + fence
+
+	case ListBlock:
+		return w.list(b)
+
+	case QuoteBlock:
+		return prefixMarkdown(strings.Join(w.blocks(b.Children), "\n\n"), "> ")
+
+	case TableBlock:
+		return w.table(b)
+
+	case DefinitionsBlock:
+		var parts []string
+		for _, child := range b.Children {
+			switch child.Kind {
+			case TermBlock:
+				if text := w.inline(child.Inline); text != "" {
+					parts = append(parts, "**"+text+"**")
+				}
+			case DescriptionBlock:
+				parts = append(parts, w.blocks(child.Children)...)
+			}
+		}
+		return strings.Join(parts, "\n\n")
+
+	case CaptionBlock:
+		return "*" + w.inline(b.Inline) + "*"
+
+	case RuleBlock:
+		return "---"
+
+	case ImageBlock:
+		return w.inline(b.Inline)
+
+	default:
+		return escapeBlockStart(w.inline(b.Inline))
+	}
+}
+
+func (w *markdownWriter) list(b Block) string {
+	var items []string
+	loose := false
+	for i, item := range b.Children {
+		marker := "- "
+		if b.Ordered {
+			marker = strconv.Itoa(b.Start+i) + ". "
+		}
+
+		blocks := w.blocks(item.Children)
+		if len(blocks) == 0 {
+			continue
+		}
+
+		// an item is tight when everything after its text is a nested list
+		sep := "\n"
+		for _, child := range item.Children[1:] {
+			if child.Kind != ListBlock {
+				sep = "\n\n"
+				loose = true
+			}
+		}
+		items = append(items, marker+indentMarkdown(strings.Join(blocks, sep), len(marker)))
+	}
+
+	if loose {
+		return strings.Join(items, "\n\n")
+	}
+	return strings.Join(items, "\n")
+}
+
+// renders a GitHub flavoured Markdown table, the first row is the header
+func (w *markdownWriter) table(b Block) string {
+	cols := 0
+	for _, row := range b.Children {
+		cols = max(cols, len(row.Children))
+	}
+
+	line := func(row Block) string {
+		cells := make([]string, cols)
+		for i, cell := range row.Children {
+			text := strings.ReplaceAll(w.inline(cell.Inline), "|", `\|`)
+			cells[i] = strings.ReplaceAll(text, "\\\n", " ")
+		}
+		return "| " + strings.Join(cells, " | ") + " |"
+	}
+
+	lines := []string{line(b.Children[0]), "|" + strings.Repeat(" --- |", cols)}
+	for _, row := range b.Children[1:] {
+		lines = append(lines, line(row))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// returns inline content as Markdown, whitespace collapsed like a browser would
+func (w *markdownWriter) inline(inline []Inline) string {
+	var b strings.Builder
+	for i := 0; i < len(inline); {
+		// a run of text in the same link
+		j := i + 1
+		for j < len(inline) && inline[j].Link == inline[i].Link {
+			j++
+		}
+
+		text := w.styled(inline[i:j])
+		if n := inline[i].Link; n != 0 && strings.TrimSpace(text) != "" {
+			writeWrapped(&b, text, "[", "]("+w.doc.Links[n-1].URL+")")
+		} else {
+			writeText(&b, text)
+		}
+		i = j
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// returns Markdown for runs of text, merging neighbours with the same style
+func (w *markdownWriter) styled(inline []Inline) string {
+	var b strings.Builder
+	for i := 0; i < len(inline); {
+		in := inline[i]
+		switch {
+		case in.Image != nil:
+			if in.Image.URL != "" {
+				writeText(&b, "!["+escapeMarkdown(in.Image.Alt)+"]("+in.Image.URL+")")
+			}
+			i++
+			continue
+		case in.Text == "\n":
+			b.WriteString("\\\n")
+			i++
+			continue
+		}
+
+		var text strings.Builder
+		j := i
+		for j < len(inline) && inline[j].Image == nil && inline[j].Text != "\n" && inline[j].Style == in.Style {
+			text.WriteString(inline[j].Text)
+			j++
+		}
+		i = j
+
+		collapsed := collapseSpace(text.String())
+		if in.Style&Code != 0 {
+			writeWrapped(&b, collapsed, codeSpan(strings.TrimSpace(collapsed)))
+			continue
+		}
+
+		open := ""
+		if in.Style&Bold != 0 {
+			open += "**"
+		}
+		if in.Style&Italic != 0 {
+			open += "*"
+		}
+		if in.Style&Strikethrough != 0 {
+			open += "~~"
+		}
+		writeWrapped(&b, escapeMarkdown(collapsed), open, reverse(open))
+	}
+	return b.String()
+}
+
+// writes text between delimiters, keeping surrounding spaces outside of
+// them. a single delimiter replaces the text, e.g. a code span
+func writeWrapped(b *strings.Builder, text string, delims ...string) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		writeText(b, text)
+		return
+	}
+	if strings.HasPrefix(text, " ") {
+		writeText(b, " ")
+	}
+	switch len(delims) {
+	case 1:
+		b.WriteString(delims[0])
+	case 2:
+		b.WriteString(delims[0] + trimmed + delims[1])
+	}
+	if strings.HasSuffix(text, " ") {
+		b.WriteString(" ")
+	}
+}
+
+func codeSpan(code string) string {
+	ticks := strings.Repeat("`", longestRun(code, '`')+1)
+	pad := ""
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		pad = " "
+	}
+	return ticks + pad + code + pad + ticks
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// appends text, not doubling up spaces between inline elements
+func writeText(b *strings.Builder, text string) {
+	s := b.String()
+	if strings.HasPrefix(text, " ") && (s == "" || strings.HasSuffix(s, " ") || strings.HasSuffix(s, "\n")) {
+		text = strings.TrimLeft(text, " ")
+	}
+	b.WriteString(text)
+}
+
+func collapseSpace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`,
+)
+
+// escapes characters that would otherwise be read as inline formatting
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// escapes a paragraph that would otherwise start a heading, quote or list
+func escapeBlockStart(text string) string {
+	switch {
+	case strings.HasPrefix(text, "#"), strings.HasPrefix(text, ">"),
+		strings.HasPrefix(text, "- "), strings.HasPrefix(text, "+ "):
+		return `\` + text
+	}
+
+	digits := strings.IndexFunc(text, func(r rune) bool { return r < '0' || r > '9' })
+	if digits > 0 && strings.HasPrefix(text[digits:], ". ") {
+		return text[:digits] + `\` + text[digits:]
+	}
+	return text
+}
+
+// indents every line but the first, so it lines up after a list marker
+func indentMarkdown(text string, n int) string {
+	indent := strings.Repeat(" ", n)
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func prefixMarkdown(text, prefix string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(prefix+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func longestRun(s string, c rune) int {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == c {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
@@ -0,0 +1,102 @@
+package content
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "inline formatting",
+			html: `<p>Some <b>bold</b>, <em> italic </em>and <code>a*b</code> text with a <a href="/docs">link</a>.</p>`,
+			want: "Some **bold**, *italic* and `a*b` text with a [link](https://example.com/docs).",
+		},
+		{
+			name: "headings and rules",
+			html: `<h2>Title</h2><p>text</p><hr>`,
+			want: "## Title\n\ntext\n\n---",
+		},
+		{
+			name: "code block keeps its language",
+			html: "<pre><code class=\"language-go\">if x {\n\treturn\n}</code></pre>",
+			want: "```go\nif x {\n\treturn\n}\n```",
+		},
+		{
+			name: "nested lists",
+			html: `<ol><li>one<ul><li>nested</li></ul></li><li>two</li></ol>`,
+			want: "1. one\n   - nested\n2. two",
+		},
+		{
+			name: "blockquote",
+			html: `<blockquote><p>first</p><p>second</p></blockquote>`,
+			want: "> first\n>\n> second",
+		},
+		{
+			name: "table",
+			html: `<table><tr><th>Name</th><th>Lang</th></tr><tr><td>ohnurr</td><td>Go | C</td></tr></table>`,
+			want: "| Name | Lang |\n| --- | --- |\n| ohnurr | Go \\| C |",
+		},
+		{
+			name: "images",
+			html: `<p><img src="/a.png" alt="A chart"></p>`,
+			want: "![A chart](https://example.com/a.png)",
+		},
+		{
+			name: "text that looks like markdown is escaped",
+			html: `<p># not a heading</p><p>snake_case and [brackets]</p>`,
+			want: "\\# not a heading\n\nsnake\\_case and \\[brackets\\]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderMarkdown(tt.html, "https://example.com/post")
+			if err != nil {
+				t.Fatalf("RenderMarkdown() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RenderMarkdown() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	meta := Metadata{
+		Title:   `A "quoted" title`,
+		Authors: []string{"Ada"},
+		Feed:    "Blog",
+		URL:     "https://example.com/post",
+		Date:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	got, err := ExportMarkdown(meta, "<p>Hello</p>")
+	if err != nil {
+		t.Fatalf("ExportMarkdown() error = %v", err)
+	}
+
+	want := strings.Join([]string{
+		"---",
+		`title: "A \"quoted\" title"`,
+		"author:",
+		`  - "Ada"`,
+		`feed: "Blog"`,
+		`url: "https://example.com/post"`,
+		"date: 2024-05-01T12:00:00Z",
+		"---",
+		"",
+		`# A "quoted" title`,
+		"",
+		"Hello",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("ExportMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
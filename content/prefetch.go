@@ -0,0 +1,142 @@
+package content
+
+import (
+	"net/url"
+	"os"
+	"sync"
+)
+
+// Prefetch extracts the given articles into the cache in the background.
+// at most concurrency articles are fetched at once, and at most perHost
+// from any single host. articles another prefetch is already fetching are
+// left to it. returns the number of articles fetched and failed
+func (c *Cache) Prefetch(urls []string, concurrency, perHost int) (fetched, failed int) {
+	concurrency = max(concurrency, 1)
+	perHost = max(perHost, 1)
+
+	pending := c.claim(urls)
+	defer c.release(pending)
+
+	hosts := make(map[string]chan struct{})
+	for _, u := range pending {
+		host := hostOf(u)
+		if _, ok := hosts[host]; !ok {
+			hosts[host] = make(chan struct{}, perHost)
+		}
+	}
+
+	queue := make(chan string)
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	for range min(concurrency, len(pending)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for articleURL := range queue {
+				hostSem := hosts[hostOf(articleURL)]
+				hostSem <- struct{}{}
+				err := c.warm(articleURL)
+				<-hostSem
+
+				mu.Lock()
+				if err != nil {
+					failed++
+				} else {
+					fetched++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, u := range pending {
+		queue <- u
+	}
+	close(queue)
+
+	wg.Wait()
+	return fetched, failed
+}
+
+// marks the articles that aren't cached or being prefetched already as
+// being prefetched, and returns them without duplicates
+func (c *Cache) claim(urls []string) []string {
+	var uncached []string
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+		if !c.cached(u) {
+			uncached = append(uncached, u)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var claimed []string
+	for _, u := range uncached {
+		if c.prefetching[u] {
+			continue
+		}
+		c.prefetching[u] = true
+		claimed = append(claimed, u)
+	}
+	return claimed
+}
+
+func (c *Cache) release(urls []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, u := range urls {
+		delete(c.prefetching, u)
+	}
+}
+
+// reports whether an article is cached in memory or on disk
+func (c *Cache) cached(articleURL string) bool {
+	if c.Has(articleURL) {
+		return true
+	}
+	if c.dir == "" {
+		return false
+	}
+	_, err := os.Stat(c.diskPath(articleURL))
+	return err == nil
+}
+
+// extracts an article and its images to disk without loading it into
+// memory, so prefetching doesn't push recently read articles out
+func (c *Cache) warm(articleURL string) error {
+	if c.dir == "" {
+		return c.Load(articleURL)
+	}
+
+	articleHTML, err := ExtractArticle(articleURL)
+	if err != nil {
+		return err
+	}
+	if err := c.writeDisk(articleURL, articleHTML); err != nil {
+		return err
+	}
+
+	// so the article has its images offline too
+	if images := c.currentImages(); images != nil {
+		images.fetch(articleHTML, articleURL)
+	}
+	return nil
+}
+
+func hostOf(articleURL string) string {
+	u, err := url.Parse(articleURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
@@ -0,0 +1,102 @@
+package content
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// counts how many requests a server handles at once
+type loadCounter struct {
+	mu       sync.Mutex
+	current  int
+	peak     int
+	requests map[string]int
+}
+
+func (l *loadCounter) enter(path string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.current++
+	l.peak = max(l.peak, l.current)
+	if l.requests == nil {
+		l.requests = make(map[string]int)
+	}
+	l.requests[path]++
+}
+
+func (l *loadCounter) leave() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.current--
+}
+
+func TestCache_Prefetch(t *testing.T) {
+	page := "<html><head><title>Post</title></head><body><article><h1>Post</h1><p>" +
+		strings.Repeat("Some words worth reading about prefetching. ", 20) +
+		"</p></article></body></html>"
+
+	var total loadCounter
+	newServer := func(host *loadCounter) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host.enter(r.URL.Path)
+			total.enter(r.URL.Path)
+			time.Sleep(20 * time.Millisecond)
+			total.leave()
+			host.leave()
+
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, page)
+		}))
+	}
+
+	var a, b loadCounter
+	srvA, srvB := newServer(&a), newServer(&b)
+	defer srvA.Close()
+	defer srvB.Close()
+
+	var urls []string
+	for i := range 6 {
+		urls = append(urls, fmt.Sprintf("%s/%d", srvA.URL, i), fmt.Sprintf("%s/%d", srvB.URL, i))
+	}
+	// duplicates are only fetched once
+	urls = append(urls, urls[:4]...)
+
+	c := newCache(1, t.TempDir())
+	fetched, failed := c.Prefetch(urls, 3, 2)
+
+	if fetched != 12 || failed != 0 {
+		t.Errorf("Prefetch() = %d, %d, want 12, 0", fetched, failed)
+	}
+	if total.peak > 3 {
+		t.Errorf("%d articles fetched at once, want at most 3", total.peak)
+	}
+	for name, host := range map[string]*loadCounter{"a": &a, "b": &b} {
+		if host.peak > 2 {
+			t.Errorf("%d articles fetched at once from %s, want at most 2", host.peak, name)
+		}
+		for path, n := range host.requests {
+			if n != 1 {
+				t.Errorf("%s%s fetched %d times, want once", name, path, n)
+			}
+		}
+	}
+
+	// everything is on disk now, so nothing is fetched again
+	if fetched, failed := c.Prefetch(urls, 3, 2); fetched != 0 || failed != 0 {
+		t.Errorf("second Prefetch() = %d, %d, want 0, 0", fetched, failed)
+	}
+}
+
+func TestCache_Prefetch_skipsInFlight(t *testing.T) {
+	c := newCache(1, t.TempDir())
+	c.prefetching["https://example.com/post"] = true
+
+	if got := c.claim([]string{"https://example.com/post", "https://example.com/other"}); len(got) != 1 || got[0] != "https://example.com/other" {
+		t.Errorf("claim() = %v, want [https://example.com/other]", got)
+	}
+}
@@ -0,0 +1,380 @@
+package content
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2/styles"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// styles of rendered articles, set from the theme
+var (
+	h1Style         lg.Style
+	h2Style         lg.Style
+	h3Style         lg.Style
+	headerStyle     lg.Style
+	codeBlockStyle  lg.Style
+	codeInlineStyle lg.Style
+	footnoteStyle   lg.Style
+	dimTextStyle    lg.Style
+	captionStyle    lg.Style
+)
+
+// colours articles are rendered in. colours are 256-colour palette numbers
+// or #rrggbb, "" leaves the terminal's default
+type Theme struct {
+	Heading              string // h1 and h2
+	Subheading           string // h3 and below
+	Code                 string // code blocks
+	CodeBackground       string
+	InlineCode           string
+	InlineCodeBackground string
+	Footnote             string // link footnote numbers
+	Dim                  string // table borders, rules, references
+	Caption              string // image and table captions
+	Syntax               string // chroma style code is highlighted with, "" for none
+}
+
+var defaultTheme = Theme{
+	Heading: "147", Subheading: "111", Code: "229", CodeBackground: "235", InlineCode: "223",
+	InlineCodeBackground: "236", Footnote: "103", Dim: "240", Caption: "245", Syntax: "monokai",
+}
+
+func init() {
+	SetTheme(defaultTheme)
+}
+
+// styles articles with the theme's colours. rendered articles aren't
+// restyled, so set it before rendering any
+func SetTheme(t Theme) {
+	h1Style = lg.NewStyle().
+		Foreground(lg.Color(t.Heading)).
+		Bold(true).
+		Underline(true)
+
+	h2Style = lg.NewStyle().
+		Foreground(lg.Color(t.Heading)).
+		Bold(true)
+
+	h3Style = lg.NewStyle().
+		Foreground(lg.Color(t.Subheading)).
+		Bold(true)
+
+	headerStyle = lg.NewStyle().
+		Foreground(lg.Color(t.Subheading)).
+		Bold(true)
+
+	codeBlockStyle = lg.NewStyle().
+		Foreground(lg.Color(t.Code)).
+		Background(lg.Color(t.CodeBackground)).
+		Padding(1, 2)
+
+	codeInlineStyle = lg.NewStyle().
+		Foreground(lg.Color(t.InlineCode)).
+		Background(lg.Color(t.InlineCodeBackground))
+
+	footnoteStyle = lg.NewStyle().
+		Foreground(lg.Color(t.Footnote))
+
+	dimTextStyle = lg.NewStyle().
+		Foreground(lg.Color(t.Dim))
+
+	captionStyle = lg.NewStyle().
+		Foreground(lg.Color(t.Caption)).
+		Italic(true)
+
+	tableBorderStyle = lg.NewStyle().
+		Foreground(lg.Color(t.Dim))
+
+	codeTheme = nil
+	if t.Syntax != "" {
+		codeTheme = styles.Get(t.Syntax)
+	}
+}
+
+// list bullets by nesting depth
+var bullets = []string{"•", "◦", "▪"}
+
+// Renderer turns a document into text in some format
+type Renderer interface {
+	Render(doc *Document) (string, error)
+}
+
+// renders documents as styled text for the terminal, wrapped to Width
+type TerminalRenderer struct {
+	Width int
+}
+
+func (t TerminalRenderer) Render(doc *Document) (string, error) {
+	return renderTerminal(doc, t.Width, nil).Text, nil
+}
+
+// renders documents as unstyled text wrapped to Width, links still
+// numbered as footnotes
+type PlainRenderer struct {
+	Width int
+}
+
+func (p PlainRenderer) Render(doc *Document) (string, error) {
+	text := renderTerminal(doc, p.Width, nil).Text
+	lines := strings.Split(ansi.Strip(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// an article rendered for the terminal along with the links found in it
+type RenderedArticle struct {
+	Text  string
+	Links []Link // footnote [n] is Links[n-1]
+
+	graphics map[int]graphic // by the line the image starts on
+}
+
+// renders extracted article HTML as styled terminal text wrapped to the
+// given width. links are numbered as footnotes and resolved against baseURL
+func RenderArticle(articleHTML, baseURL string, width int) (*RenderedArticle, error) {
+	return renderArticle(articleHTML, baseURL, width, nil)
+}
+
+// renders an article drawing its downloaded images, images shows alt text when nil
+func renderArticle(articleHTML, baseURL string, width int, images *imageStore) (*RenderedArticle, error) {
+	doc, err := ParseDocument(articleHTML, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return renderTerminal(doc, width, images), nil
+}
+
+func renderTerminal(doc *Document, width int, images *imageStore) *RenderedArticle {
+	r := &renderer{doc: doc, images: images, footnotes: make(map[int]int)}
+
+	lines := r.renderBlocks(doc.Blocks, width, false)
+	lines = append(lines, r.renderReferences()...)
+
+	return &RenderedArticle{
+		Text:     strings.Join(lines, "\n"),
+		Links:    r.links,
+		graphics: locateGraphics(lines, r.graphics),
+	}
+}
+
+// finds the line each protocol image ended up on after layout
+func locateGraphics(lines []string, graphics []graphic) map[int]graphic {
+	if len(graphics) == 0 {
+		return nil
+	}
+	located := make(map[int]graphic)
+	for i, line := range lines {
+		for _, g := range graphics {
+			if strings.Contains(line, g.escape) {
+				located[i] = g
+				break
+			}
+		}
+	}
+	return located
+}
+
+// returns lines start to end of the text. images drawn with a graphics
+// protocol that don't fit above end are left out, drawing them would
+// scroll the terminal
+func (a *RenderedArticle) VisibleLines(start, end int) []string {
+	lines := strings.Split(a.Text, "\n")
+	end = min(end, len(lines))
+	start = min(max(start, 0), end)
+
+	visible := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		line := lines[i]
+		if g, ok := a.graphics[i]; ok && i+g.rows > end {
+			line = strings.Replace(line, g.escape, "", 1)
+		}
+		visible = append(visible, line)
+	}
+	return visible
+}
+
+type renderer struct {
+	doc       *Document
+	listDepth int
+	images    *imageStore // nil when images are shown as alt text
+	graphics  []graphic
+	links     []Link      // footnote [n] is links[n-1]
+	footnotes map[int]int // document link number -> footnote number
+}
+
+// returns the footnote number of a document link. links are numbered as
+// they're shown, so a link around an image that's drawn gets none
+func (r *renderer) footnote(link int) int {
+	if n, ok := r.footnotes[link]; ok {
+		return n
+	}
+	r.links = append(r.links, r.doc.Links[link-1])
+	r.footnotes[link] = len(r.links)
+	return len(r.links)
+}
+
+// lays out blocks separated by a blank line. within list items and
+// definitions nested lists hug the text they belong to
+func (r *renderer) renderBlocks(blocks []Block, width int, inItem bool) []string {
+	var lines []string
+	first := true
+	for _, b := range blocks {
+		blockLines := r.renderBlock(b, width)
+		if len(blockLines) == 0 {
+			continue
+		}
+		if !first && !(inItem && b.Kind == ListBlock) {
+			lines = append(lines, "")
+		}
+		lines = append(lines, blockLines...)
+		first = false
+	}
+	return lines
+}
+
+func (r *renderer) renderBlock(b Block, width int) []string {
+	// deeply nested blocks run out of room, they still get a column
+	width = max(width, 1)
+
+	switch b.Kind {
+	case HeadingBlock:
+		style := headerStyle
+		switch b.Level {
+		case 1:
+			style = h1Style
+		case 2:
+			style = h2Style
+		case 3:
+			style = h3Style
+		}
+		return layoutSpans(r.spans(b.Inline), width, style)
+
+	case CodeBlock:
+		return highlightCode(b.Code, b.Language, width)
+
+	case ListBlock:
+		return r.renderList(b, width)
+
+	case QuoteBlock:
+		bar := dimTextStyle.Render("│") + " "
+		return prefixLines(r.renderBlocks(b.Children, max(width-2, 1), false), bar)
+
+	case TableBlock:
+		return r.renderTable(b, width)
+
+	case DefinitionsBlock:
+		return r.renderDefinitions(b, width)
+
+	case CaptionBlock:
+		return layoutSpans(r.spans(b.Inline), width, captionStyle)
+
+	case RuleBlock:
+		return []string{dimTextStyle.Render(strings.Repeat("─", width))}
+
+	case ImageBlock:
+		if lines := r.renderImage(b, width); lines != nil {
+			return lines
+		}
+		// not downloaded or images are off, fall back to the alt text
+		return layoutSpans(r.spans(b.Inline), width, lg.NewStyle())
+
+	default:
+		return layoutSpans(r.spans(b.Inline), width, lg.NewStyle())
+	}
+}
+
+func (r *renderer) renderList(b Block, width int) []string {
+	width = max(width, 1)
+
+	// line numbers up so "9." and "10." items align
+	markerWidth := 1
+	if b.Ordered {
+		markerWidth = len(strconv.Itoa(b.Start+len(b.Children)-1)) + 1
+	}
+
+	bullet := bullets[min(r.listDepth, len(bullets)-1)]
+	r.listDepth++
+	defer func() { r.listDepth-- }()
+
+	var lines []string
+	for i, item := range b.Children {
+		marker := bullet
+		if b.Ordered {
+			marker = fmt.Sprintf("%*s", markerWidth, strconv.Itoa(b.Start+i)+".")
+		}
+		marker = "  " + marker + " "
+
+		itemLines := r.renderBlocks(item.Children, max(width-lg.Width(marker), 1), true)
+		if len(itemLines) == 0 {
+			continue
+		}
+		lines = append(lines, hangingIndent(itemLines, marker)...)
+	}
+	return lines
+}
+
+func (r *renderer) renderDefinitions(b Block, width int) []string {
+	width = max(width, 1)
+
+	var lines []string
+	for _, child := range b.Children {
+		switch child.Kind {
+		case TermBlock:
+			lines = append(lines, layoutSpans(r.spans(child.Inline), width, lg.NewStyle().Bold(true))...)
+		case DescriptionBlock:
+			lines = append(lines, prefixLines(r.renderBlocks(child.Children, max(width-4, 1), true), "    ")...)
+		}
+	}
+	return lines
+}
+
+// converts inline content to styled spans, links are underlined and
+// followed by their footnote number
+func (r *renderer) spans(inline []Inline) []span {
+	var spans []span
+	for i, in := range inline {
+		style := in.Style
+		if in.Link != 0 {
+			style |= Underline
+		}
+
+		switch {
+		case in.Image == nil:
+			spans = append(spans, span{text: in.Text, style: style})
+		case in.Image.Alt != "":
+			spans = append(spans, span{text: " [Image: " + in.Image.Alt + "] ", style: styleDim})
+		default:
+			spans = append(spans, span{text: " [Image] ", style: styleDim})
+		}
+
+		if in.Link != 0 && (i == len(inline)-1 || inline[i+1].Link != in.Link) {
+			spans = append(spans, span{text: fmt.Sprintf("[%d]", r.footnote(in.Link)), style: styleFootnote})
+		}
+	}
+	return spans
+}
+
+// returns inline content as text with whitespace collapsed like a browser
+// would, and links marked as footnotes e.g. "text[3]"
+func (r *renderer) inlineText(inline []Inline) string {
+	return plainText(r.spans(inline))
+}
+
+// lists every footnote at the end of the article
+func (r *renderer) renderReferences() []string {
+	if len(r.links) == 0 {
+		return nil
+	}
+
+	lines := []string{"", h2Style.Render("Links"), ""}
+	for i, link := range r.links {
+		lines = append(lines, footnoteStyle.Render(fmt.Sprintf("[%d]", i+1))+" "+link.URL)
+	}
+	return lines
+}
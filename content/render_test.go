@@ -0,0 +1,210 @@
+package content
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRenderArticle_linkFootnotes(t *testing.T) {
+	html := `<p>Read <a href="/docs">the docs</a> and
+		<a href="https://example.org/">this</a>, or <a href="/docs">the docs</a> again.
+		<a href="#top">Back to top</a></p>`
+
+	got, err := RenderArticle(html, "https://example.com/post", 80)
+	if err != nil {
+		t.Fatalf("RenderArticle() error = %v", err)
+	}
+
+	wantText := "Read the docs[1] and this[2], or the docs[1] again. Back to top"
+	if !strings.HasPrefix(got.Text, wantText) {
+		t.Errorf("RenderArticle() text = %q, want prefix %q", got.Text, wantText)
+	}
+
+	wantLinks := []Link{
+		{Text: "the docs", URL: "https://example.com/docs"},
+		{Text: "this", URL: "https://example.org/"},
+	}
+	if len(got.Links) != len(wantLinks) {
+		t.Fatalf("RenderArticle() links = %v, want %v", got.Links, wantLinks)
+	}
+	for i := range wantLinks {
+		if got.Links[i] != wantLinks[i] {
+			t.Errorf("link %d = %v, want %v", i+1, got.Links[i], wantLinks[i])
+		}
+	}
+
+	if !strings.Contains(got.Text, "[2] https://example.org/") {
+		t.Errorf("RenderArticle() text missing references section: %q", got.Text)
+	}
+}
+
+func TestRenderArticle_tables(t *testing.T) {
+	html := `<table>
+		<thead><tr><th>Name</th><th>Lang</th></tr></thead>
+		<tbody>
+			<tr><td>ohnurr</td><td>Go</td></tr>
+			<tr><td>newsboat</td><td>C++</td></tr>
+		</tbody>
+	</table>`
+
+	t.Run("box drawn when it fits", func(t *testing.T) {
+		got, err := RenderArticle(html, "", 80)
+		if err != nil {
+			t.Fatalf("RenderArticle() error = %v", err)
+		}
+
+		want := []string{
+			"┌──────────┬──────┐",
+			"│ Name     │ Lang │",
+			"├──────────┼──────┤",
+			"│ ohnurr   │ Go   │",
+			"│ newsboat │ C++  │",
+			"└──────────┴──────┘",
+		}
+		for _, line := range want {
+			if !strings.Contains(got.Text, line) {
+				t.Errorf("RenderArticle() missing line %q in:\n%s", line, got.Text)
+			}
+		}
+	})
+
+	t.Run("vertical when too narrow", func(t *testing.T) {
+		got, err := RenderArticle(html, "", 15)
+		if err != nil {
+			t.Fatalf("RenderArticle() error = %v", err)
+		}
+
+		for _, line := range []string{"Name: ohnurr", "Lang: Go", "Name: newsboat", "Lang: C++"} {
+			if !strings.Contains(got.Text, line) {
+				t.Errorf("RenderArticle() missing line %q in:\n%s", line, got.Text)
+			}
+		}
+		if strings.Contains(got.Text, "┌") {
+			t.Errorf("RenderArticle() drew a box at width 15:\n%s", got.Text)
+		}
+	})
+
+	t.Run("out of room in nested quotes", func(t *testing.T) {
+		nested := strings.Repeat("<blockquote>", 11) + html + strings.Repeat("</blockquote>", 11)
+		got, err := RenderArticle(nested, "", 20)
+		if err != nil {
+			t.Fatalf("RenderArticle() error = %v", err)
+		}
+		if !strings.Contains(got.Text, "C") {
+			t.Errorf("RenderArticle() lost the table:\n%s", got.Text)
+		}
+	})
+}
+
+func Test_wrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{"fits", "one two", 10, []string{"one two"}},
+		{"wraps", "one two three", 7, []string{"one two", "three"}},
+		{"breaks long words", "abcdef", 4, []string{"abcd", "ef"}},
+		{"wide characters", "漢字", 3, []string{"漢", "字"}},
+		{"no room", "ab c", 0, []string{"a", "b", "c"}},
+		{"negative width", "ab", -3, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapText(tt.text, tt.width); !slices.Equal(got, tt.want) {
+				t.Errorf("wrapText(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderArticle_layout(t *testing.T) {
+	tests := []struct {
+		name  string
+		html  string
+		width int
+		want  string
+	}{
+		{
+			name:  "paragraph wraps to width",
+			html:  `<p>one two three four five six</p>`,
+			width: 10,
+			want:  "one two\nthree four\nfive six",
+		},
+		{
+			name:  "inline formatting keeps surrounding spaces",
+			html:  `<p>a <b>bold</b>, <em>italic</em> and <code>x</code>y</p>`,
+			width: 80,
+			want:  "a bold, italic and xy",
+		},
+		{
+			name:  "nested lists are indented",
+			html:  `<ul><li>one<ul><li>nested</li></ul></li><li>two</li></ul>`,
+			width: 80,
+			want:  "  • one\n      ◦ nested\n  • two",
+		},
+		{
+			name:  "ordered list numbers line up",
+			html:  `<ol start="9"><li>nine</li><li>ten</li></ol>`,
+			width: 80,
+			want:  "   9. nine\n  10. ten",
+		},
+		{
+			name:  "words are hyphenated where they have a hyphen",
+			html:  `<p>a well-known fact</p>`,
+			width: 8,
+			want:  "a well-\nknown\nfact",
+		},
+		{
+			name:  "soft hyphens are only shown when a word is broken there",
+			html:  "<p>extra&shy;ordinary extra&shy;ordinary</p>",
+			width: 20,
+			want:  "extraordinary extra-\nordinary",
+		},
+		{
+			name:  "words longer than a line are broken",
+			html:  `<p>incomprehensibilities</p>`,
+			width: 10,
+			want:  "incompreh-\nensibilit-\nies",
+		},
+		{
+			name:  "URLs are broken without a hyphen",
+			html:  `<p>https://example.com/a/long/path</p>`,
+			width: 12,
+			want:  "https://exam\nple.com/a/lo\nng/path",
+		},
+		{
+			name:  "CJK text wraps between characters",
+			html:  `<p>日本語の文章です</p>`,
+			width: 6,
+			want:  "日本語\nの文章\nです",
+		},
+		{
+			name:  "definition list",
+			html:  `<dl><dt>Term</dt><dd>Meaning</dd></dl>`,
+			width: 80,
+			want:  "Term\n    Meaning",
+		},
+		{
+			name:  "blocks are separated by a blank line",
+			html:  `<p>first</p><hr><figure>[fig]<figcaption>cap</figcaption></figure>`,
+			width: 5,
+			want:  "first\n\n─────\n\n[fig]\n\ncap",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderArticle(tt.html, "", tt.width)
+			if err != nil {
+				t.Fatalf("RenderArticle() error = %v", err)
+			}
+			if got.Text != tt.want {
+				t.Errorf("RenderArticle() = %q, want %q", got.Text, tt.want)
+			}
+		})
+	}
+}
@@ -6,43 +6,49 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
-	"github.com/PuerkitoBio/goquery"
-	lg "github.com/charmbracelet/lipgloss"
 	readability "github.com/go-shiori/go-readability"
 )
 
-var (
-	h1Style = lg.NewStyle().
-		Foreground(lg.Color("147")).
-		Bold(true).
-		Underline(true)
+// shared client so a stalled server can't hang a fetch (or a prefetch) forever
+var httpClient = &http.Client{Timeout: 30 * time.Second}
 
-	h2Style = lg.NewStyle().
-		Foreground(lg.Color("147")).
-		Bold(true)
+// width used when rendering without a terminal to fit
+const defaultWidth = 80
 
-	h3Style = lg.NewStyle().
-		Foreground(lg.Color("111")).
-		Bold(true)
-
-	headerStyle = lg.NewStyle().
-			Foreground(lg.Color("111")).
-			Bold(true)
+// fetches an article and renders it for the terminal
+func GetArticleContent(articleURL string) (string, error) {
+	articleHTML, err := ExtractArticle(articleURL)
+	if err != nil {
+		return "", err
+	}
+	doc, err := ParseDocument(articleHTML, articleURL)
+	if err != nil {
+		return "", err
+	}
+	return TerminalRenderer{Width: defaultWidth}.Render(doc)
+}
 
-	codeBlockStyle = lg.NewStyle().
-			Foreground(lg.Color("229")).
-			Background(lg.Color("235")).
-			Padding(1, 2)
+// fetches a page and extracts the main article HTML with readability
+func ExtractArticle(articleURL string) (string, error) {
+	articleHTML, _, err := ExtractWithMetadata(articleURL)
+	return articleHTML, err
+}
 
-	codeInlineStyle = lg.NewStyle().
-			Foreground(lg.Color("223")).
-			Background(lg.Color("236"))
-)
+// fetches a page and extracts the main article HTML along with the
+// metadata readability finds on the page
+func ExtractWithMetadata(articleURL string) (string, Metadata, error) {
+	page, err := Fetch(articleURL)
+	if err != nil {
+		return "", Metadata{}, err
+	}
+	return Extract(page, articleURL)
+}
 
-func GetArticleContent(articleURL string) (string, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", articleURL, nil)
+// downloads the HTML of a page
+func Fetch(pageURL string) (string, error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -52,7 +58,7 @@ func GetArticleContent(articleURL string) (string, error) {
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
 
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch page: %w", err)
 	}
@@ -66,129 +72,28 @@ func GetArticleContent(articleURL string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to read response: %w", err)
 	}
+	return string(htmlBytes), nil
+}
 
-	// parse with readability
-	parsedURL, _ := url.Parse(articleURL)
-	article, err := readability.FromReader(strings.NewReader(string(htmlBytes)), parsedURL)
+// extracts the main article HTML from a page with readability, along with
+// the title, author and date it finds. pageURL resolves relative links
+func Extract(page, pageURL string) (string, Metadata, error) {
+	parsedURL, _ := url.Parse(pageURL)
+	article, err := readability.FromReader(strings.NewReader(page), parsedURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse article: %w", err)
+		return "", Metadata{}, fmt.Errorf("failed to parse article: %w", err)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(article.Content))
-	if err != nil {
-		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	meta := Metadata{
+		Title: article.Title,
+		Feed:  article.SiteName,
+		URL:   pageURL,
 	}
-
-	var output strings.Builder
-
-	// process elements
-	doc.Find("body").Contents().Each(func(i int, s *goquery.Selection) {
-		renderNode(&output, s)
-	})
-
-	return output.String(), nil
-}
-
-func renderNode(output *strings.Builder, s *goquery.Selection) {
-	nodeName := goquery.NodeName(s)
-
-	switch nodeName {
-	case "p":
-		text := strings.TrimSpace(s.Text())
-		if text != "" {
-			output.WriteString(text)
-			output.WriteString("\n\n")
-		}
-
-	case "h1", "h2", "h3", "h4", "h5", "h6":
-		text := strings.TrimSpace(s.Text())
-		if text != "" {
-			output.WriteString("\n")
-			switch nodeName {
-			case "h1":
-				output.WriteString(h1Style.Render(text))
-			case "h2":
-				output.WriteString(h2Style.Render(text))
-			case "h3":
-				output.WriteString(h3Style.Render(text))
-			default:
-				output.WriteString(headerStyle.Render(text))
-			}
-			output.WriteString("\n\n")
-		}
-
-	case "pre":
-		code := s.Text()
-		if code != "" {
-			output.WriteString("\n")
-			lines := strings.SplitSeq(code, "\n")
-			for line := range lines {
-				output.WriteString(codeBlockStyle.Render(line))
-				output.WriteString("\n")
-			}
-			output.WriteString("\n")
-		}
-
-	case "code":
-		if s.Parent().Length() > 0 && goquery.NodeName(s.Parent()) != "pre" {
-			output.WriteString(codeInlineStyle.Render(s.Text()))
-		}
-
-	case "ul", "ol":
-		s.Children().Each(func(i int, li *goquery.Selection) {
-			if goquery.NodeName(li) == "li" {
-				prefix := "•"
-				if nodeName == "ol" {
-					prefix = fmt.Sprintf("%d.", i+1)
-				}
-				text := strings.TrimSpace(li.Text())
-				if text != "" {
-					fmt.Fprintf(output, "  %s %s\n", prefix, text)
-				}
-			}
-		})
-		output.WriteString("\n")
-
-	case "img":
-		alt, hasAlt := s.Attr("alt")
-		if hasAlt && alt != "" {
-			fmt.Fprintf(output, "\n[Image: %s]\n\n", alt)
-		}
-
-	case "br":
-		output.WriteString("\n")
-
-	case "blockquote":
-		text := strings.TrimSpace(s.Text())
-		if text != "" {
-			lines := strings.SplitSeq(text, "\n")
-			for line := range lines {
-				if strings.TrimSpace(line) != "" {
-					output.WriteString("│ " + line + "\n")
-				}
-			}
-			output.WriteString("\n")
-		}
-
-	case "#text":
-		// only render text nodes that are direct children of body
-		// (cuz text inside other elements is handled by those elements)
-		parent := s.Parent()
-		if parent.Length() > 0 {
-			parentName := goquery.NodeName(parent)
-			if parentName == "body" || parentName == "div" {
-				text := strings.TrimSpace(s.Text())
-				if text != "" {
-					output.WriteString(text)
-					output.WriteString("\n")
-				}
-			}
-		}
-
-	default:
-		// just recurse into children for errything else
-		s.Contents().Each(func(i int, child *goquery.Selection) {
-			renderNode(output, child)
-		})
+	if article.Byline != "" {
+		meta.Authors = []string{article.Byline}
+	}
+	if article.PublishedTime != nil {
+		meta.Date = *article.PublishedTime
 	}
+	return article.Content, meta, nil
 }
@@ -0,0 +1,225 @@
+package content
+
+import (
+	"strings"
+
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// narrowest a column may get before falling back to the vertical layout
+const minColumnWidth = 8
+
+var (
+	tableBorderStyle lg.Style // set from the theme
+
+	tableHeaderStyle = lg.NewStyle().
+				Bold(true)
+)
+
+type tableRow struct {
+	cells  []string
+	header bool
+}
+
+// renders a table as a box drawn table that fits the width,
+// or as "header: value" blocks when there are too many columns to fit
+func (r *renderer) renderTable(table Block, width int) []string {
+	width = max(width, 1)
+
+	rows := make([]tableRow, len(table.Children))
+	cols := 0
+	for i, row := range table.Children {
+		rows[i].header = row.Header
+		for _, cell := range row.Children {
+			rows[i].cells = append(rows[i].cells, r.inlineText(cell.Inline))
+		}
+		cols = max(cols, len(rows[i].cells))
+	}
+	for i := range rows {
+		for len(rows[i].cells) < cols {
+			rows[i].cells = append(rows[i].cells, "")
+		}
+	}
+
+	var lines []string
+	if caption := r.inlineText(table.Inline); caption != "" {
+		lines = append(lines, tableHeaderStyle.Render(caption))
+	}
+
+	// borders and padding take 3 columns per cell plus the closing border
+	available := width - 3*cols - 1
+	if available < cols*minColumnWidth {
+		return append(lines, verticalTable(rows, width)...)
+	}
+	return append(lines, boxTable(rows, columnWidths(rows, cols, available))...)
+}
+
+// fits columns into the available width by shrinking the widest column
+// until everything fits
+func columnWidths(rows []tableRow, cols, available int) []int {
+	widths := make([]int, cols)
+	for _, row := range rows {
+		for i, cell := range row.cells {
+			for line := range strings.SplitSeq(cell, "\n") {
+				widths[i] = max(widths[i], lg.Width(line))
+			}
+		}
+	}
+
+	for i := range widths {
+		widths[i] = max(widths[i], 1)
+	}
+
+	for sum(widths) > available {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+		widths[widest]--
+	}
+
+	return widths
+}
+
+func boxTable(rows []tableRow, widths []int) []string {
+	border := func(left, mid, right string) string {
+		parts := make([]string, len(widths))
+		for i, w := range widths {
+			parts[i] = strings.Repeat("─", w+2)
+		}
+		return tableBorderStyle.Render(left + strings.Join(parts, mid) + right)
+	}
+	bar := tableBorderStyle.Render("│")
+
+	lines := []string{border("┌", "┬", "┐")}
+
+	for i, row := range rows {
+		// wrap each cell, the row is as tall as its tallest cell
+		wrapped := make([][]string, len(row.cells))
+		height := 1
+		for j, cell := range row.cells {
+			wrapped[j] = wrapText(cell, widths[j])
+			height = max(height, len(wrapped[j]))
+		}
+
+		for l := range height {
+			var b strings.Builder
+			b.WriteString(bar)
+			for j := range row.cells {
+				text := ""
+				if l < len(wrapped[j]) {
+					text = wrapped[j][l]
+				}
+				padding := strings.Repeat(" ", max(widths[j]-lg.Width(text), 0))
+				if row.header {
+					text = tableHeaderStyle.Render(text)
+				}
+				b.WriteString(" " + text + padding + " ")
+				b.WriteString(bar)
+			}
+			lines = append(lines, b.String())
+		}
+
+		// separate the header from the body
+		if row.header && i < len(rows)-1 && !rows[i+1].header {
+			lines = append(lines, border("├", "┼", "┤"))
+		}
+	}
+
+	lines = append(lines, border("└", "┴", "┘"))
+	return lines
+}
+
+// lays each row out as "header: value" lines, for tables too wide to draw
+func verticalTable(rows []tableRow, width int) []string {
+	var headers []string
+	if rows[0].header {
+		headers = rows[0].cells
+		rows = rows[1:]
+	}
+
+	var lines []string
+	for i, row := range rows {
+		if i > 0 {
+			lines = append(lines, tableBorderStyle.Render(strings.Repeat("─", min(width, 20))))
+		}
+		for j, cell := range row.cells {
+			if cell == "" {
+				continue
+			}
+			label := ""
+			if j < len(headers) && headers[j] != "" {
+				label = headers[j] + ": "
+			}
+			for k, line := range wrapText(label+cell, width) {
+				if k == 0 && label != "" {
+					line = tableHeaderStyle.Render(label) + strings.TrimPrefix(line, label)
+				}
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+// word wraps text to width display columns, breaking words that don't fit
+func wrapText(text string, width int) []string {
+	// words are broken at least one character at a time
+	width = max(width, 1)
+
+	var lines []string
+	for para := range strings.SplitSeq(text, "\n") {
+		current := ""
+		for _, word := range strings.Fields(para) {
+			// break words longer than a whole line
+			for lg.Width(word) > width {
+				if current != "" {
+					lines = append(lines, current)
+					current = ""
+				}
+				head, tail := splitAtWidth(word, width)
+				lines = append(lines, head)
+				word = tail
+			}
+
+			switch {
+			case current == "":
+				current = word
+			case lg.Width(current)+1+lg.Width(word) <= width:
+				current += " " + word
+			default:
+				lines = append(lines, current)
+				current = word
+			}
+		}
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// splits s so the head is at most width display columns wide
+func splitAtWidth(s string, width int) (string, string) {
+	runes := []rune(s)
+	w := 0
+	for i, r := range runes {
+		rw := lg.Width(string(r))
+		if w+rw > width && i > 0 {
+			return string(runes[:i]), string(runes[i:])
+		}
+		w += rw
+	}
+	return s, ""
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
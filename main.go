@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"ohnurr/config"
+	"ohnurr/content"
 	"ohnurr/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -40,6 +41,16 @@ func main() {
 		removeFeed(os.Args[2])
 	case "list":
 		listFeeds()
+	case "export":
+		if len(os.Args) < 3 {
+			fmt.Println("Usage: ohnurr export <article-url> [file.md]")
+			os.Exit(1)
+		}
+		output := ""
+		if len(os.Args) > 3 {
+			output = os.Args[3]
+		}
+		exportArticle(os.Args[2], output)
 	case "version", "--version", "-v":
 		printVersion()
 	case "help", "--help", "-h":
@@ -109,6 +120,32 @@ func listFeeds() {
 	}
 }
 
+// writes an article as Markdown to a file, or stdout when no file is given
+func exportArticle(url, output string) {
+	articleHTML, meta, err := content.ExtractWithMetadata(url)
+	if err != nil {
+		fmt.Printf("Error fetching article: %v\n", err)
+		os.Exit(1)
+	}
+
+	doc, err := content.ExportMarkdown(meta, articleHTML)
+	if err != nil {
+		fmt.Printf("Error exporting article: %v\n", err)
+		os.Exit(1)
+	}
+
+	if output == "" {
+		fmt.Print(doc)
+		return
+	}
+
+	if err := os.WriteFile(output, []byte(doc), 0644); err != nil {
+		fmt.Printf("Error writing %s: %v\n", output, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Exported to %s\n", output)
+}
+
 func launchTUI() {
 	c, err := config.Load()
 	if err != nil {
@@ -128,8 +165,23 @@ func launchTUI() {
 		os.Exit(1)
 	}
 
-	model := ui.NewModel(c, state)
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	if err := ui.SetTheme(c.Settings.Theme); err != nil {
+		fmt.Printf("Error loading theme: %v\n", err)
+		os.Exit(1)
+	}
+
+	keys, err := ui.LoadKeyMap(c.Settings.Keys)
+	if err != nil {
+		fmt.Printf("Error loading keybindings: %v\n", err)
+		os.Exit(1)
+	}
+
+	model := ui.NewModel(c, state, keys)
+	options := []tea.ProgramOption{tea.WithAltScreen()}
+	if c.Settings.Mouse {
+		options = append(options, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(model, options...)
 
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error running program: %v\n", err)
@@ -151,6 +203,8 @@ func printHelp() {
 	fmt.Println("  ohnurr add <url>    Add RSS feed")
 	fmt.Println("  ohnurr remove <url> Remove RSS feed")
 	fmt.Println("  ohnurr list         List all feeds")
+	fmt.Println("  ohnurr export <url> [file.md]")
+	fmt.Println("                      Export an article as Markdown")
 	fmt.Println("  ohnurr version      Show version information")
 	fmt.Println("  ohnurr help         Show this help message")
 }
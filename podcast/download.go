@@ -0,0 +1,152 @@
+package podcast
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// a download is given up when no data arrives for this long
+var stallTimeout = time.Minute
+
+// called as a download progresses. total is -1 when the size is unknown
+type ProgressFunc func(written, total int64)
+
+// downloads an episode to dest, reporting progress along the way.
+// the file is written to dest.part and only renamed once complete
+func Download(episodeURL, dest string, progress ProgressFunc) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	// cancel the request when the server goes quiet, whether it's yet to
+	// answer or stopped sending halfway through
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stalled atomic.Bool
+	timer := time.AfterFunc(stallTimeout, func() {
+		stalled.Store(true)
+		cancel()
+	})
+	defer timer.Stop()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", episodeURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return stallError(&stalled, fmt.Errorf("failed to fetch episode: %w", err))
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+
+	partial := dest + ".part"
+	f, err := os.Create(partial)
+	if err != nil {
+		return err
+	}
+
+	w := &progressWriter{total: resp.ContentLength, progress: func(written, total int64) {
+		timer.Reset(stallTimeout)
+		if progress != nil {
+			progress(written, total)
+		}
+	}}
+	_, err = io.Copy(io.MultiWriter(f, w), resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(partial)
+		return stallError(&stalled, fmt.Errorf("failed to download episode: %w", err))
+	}
+
+	return os.Rename(partial, dest)
+}
+
+// replaces the cancellation error of a download that stalled with one
+// saying so
+func stallError(stalled *atomic.Bool, err error) error {
+	if stalled.Load() && errors.Is(err, context.Canceled) {
+		return fmt.Errorf("download stalled, no data for %s", stallTimeout)
+	}
+	return err
+}
+
+type progressWriter struct {
+	written  int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.written += int64(len(p))
+	if w.progress != nil {
+		w.progress(w.written, w.total)
+	}
+	return len(p), nil
+}
+
+// returns where an episode is stored: <dir>/<feed>/<file name>.
+// the file name comes from the enclosure URL, or the title if the URL has
+// none. many hosts serve every episode under the same name (media.mp3?id=…),
+// so a hash of the whole URL keeps episodes apart
+func EpisodePath(dir, feedTitle, title, episodeURL, mimeType string) string {
+	name := ""
+	if u, err := url.Parse(episodeURL); err == nil {
+		name = path.Base(u.Path)
+	}
+
+	ext := path.Ext(name)
+	if name == "" || name == "/" || name == "." || ext == "" {
+		name = title
+		ext = ""
+		if exts, err := mime.ExtensionsByType(mimeType); err == nil && len(exts) > 0 {
+			ext = exts[0]
+		}
+	} else {
+		name = strings.TrimSuffix(name, ext)
+	}
+
+	sum := sha256.Sum256([]byte(episodeURL))
+	name = sanitiseFileName(name) + "-" + hex.EncodeToString(sum[:4]) + ext
+
+	return filepath.Join(dir, sanitiseFileName(feedTitle), sanitiseFileName(name))
+}
+
+// replaces characters that aren't safe in file names
+func sanitiseFileName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < 32 {
+			return -1
+		}
+		return r
+	}, s)
+
+	s = strings.TrimSpace(s)
+	if s == "" || s == "." || s == ".." {
+		return "untitled"
+	}
+	return s
+}
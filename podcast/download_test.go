@@ -0,0 +1,91 @@
+package podcast
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEpisodePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		feed     string
+		title    string
+		url      string
+		mimeType string
+		want     string
+	}{
+		{
+			name:     "file name from URL",
+			feed:     "Some Show",
+			title:    "Episode 1",
+			url:      "https://cdn.example.com/shows/ep1.mp3?token=abc",
+			mimeType: "audio/mpeg",
+			want:     filepath.Join("dl", "Some Show", "ep1-887f0ae3.mp3"),
+		},
+		{
+			name:     "falls back to title",
+			feed:     "Some Show",
+			title:    "Episode 2: The Return",
+			url:      "https://cdn.example.com/download",
+			mimeType: "",
+			want:     filepath.Join("dl", "Some Show", "Episode 2_ The Return-689ba07b"),
+		},
+		{
+			name:     "unsafe feed title",
+			feed:     "AC/DC Fans",
+			title:    "Live",
+			url:      "https://example.com/live.m4a",
+			mimeType: "audio/mp4",
+			want:     filepath.Join("dl", "AC_DC Fans", "live-06ab9cad.m4a"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EpisodePath("dl", tt.feed, tt.title, tt.url, tt.mimeType)
+			if got != tt.want {
+				t.Errorf("EpisodePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEpisodePath_sameFileName(t *testing.T) {
+	a := EpisodePath("dl", "Show", "One", "https://cdn.example.com/media.mp3?id=1", "audio/mpeg")
+	b := EpisodePath("dl", "Show", "Two", "https://cdn.example.com/media.mp3?id=2", "audio/mpeg")
+	if a == b {
+		t.Errorf("episodes served as media.mp3 share the path %s", a)
+	}
+}
+
+func TestDownload_stalled(t *testing.T) {
+	defer func(timeout time.Duration) { stallTimeout = timeout }(stallTimeout)
+	stallTimeout = 50 * time.Millisecond
+
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		_, _ = w.Write([]byte("partial"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	dest := filepath.Join(t.TempDir(), "episode.mp3")
+	err := Download(srv.URL, dest, nil)
+	if err == nil || !strings.Contains(err.Error(), "stalled") {
+		t.Fatalf("Download() error = %v, want a stall", err)
+	}
+	if _, err := os.Stat(dest + ".part"); !os.IsNotExist(err) {
+		t.Errorf("expected the partial file to be removed")
+	}
+}
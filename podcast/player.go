@@ -0,0 +1,23 @@
+package podcast
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// builds the command that plays target (a URL or file) with the configured
+// player, e.g. "mpv --no-video"
+func PlayerCommand(player, target string) (*exec.Cmd, error) {
+	args := strings.Fields(player)
+	if len(args) == 0 {
+		return nil, errors.New("no player configured")
+	}
+
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return nil, err
+	}
+
+	args = append(args, target)
+	return exec.Command(args[0], args[1:]...), nil
+}
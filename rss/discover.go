@@ -0,0 +1,112 @@
+package rss
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// link types pages advertise their feeds with
+var feedTypes = []string{
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/feed+json",
+	"application/json",
+}
+
+// finds the feed for a URL the user typed in. the URL may be a feed, or a
+// page linking to its feeds with <link rel="alternate">, in which case the
+// first one that loads is used. the scheme defaults to https
+func Discover(url string) (*Feed, error) {
+	url, err := normaliseURL(url)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := fetch(url)
+	if err != nil {
+		return nil, err
+	}
+	if feed, err := parseFeed(resp.body, resp.contentType); err == nil {
+		f := newFeed(url, feed)
+		f.Status = resp.status
+		f.Fetched = time.Now()
+		return f, nil
+	}
+
+	links, err := feedLinks(resp.body, resp.url)
+	if err != nil {
+		return nil, err
+	}
+	if len(links) == 0 {
+		return nil, fmt.Errorf("no feed found at %s", url)
+	}
+
+	var errs []string
+	for _, link := range links {
+		feed, err := FetchFeed(link)
+		if err == nil {
+			return feed, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", link, err))
+	}
+	return nil, fmt.Errorf("no feed could be loaded from %s (%s)", url, strings.Join(errs, "; "))
+}
+
+// checks a typed in URL is a web address, adding https:// when the scheme
+// is left out
+func normaliseURL(url string) (string, error) {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return "", errors.New("no URL given")
+	}
+	if !strings.Contains(url, "://") {
+		url = "https://" + url
+	}
+
+	u, err := neturl.Parse(url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("not a web address: %s", url)
+	}
+	return u.String(), nil
+}
+
+// returns the absolute URLs of the feeds an HTML page links to, in order
+func feedLinks(page []byte, base *neturl.URL) ([]string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page: %w", err)
+	}
+
+	var links []string
+	doc.Find("link[rel][href]").Each(func(_ int, s *goquery.Selection) {
+		rel, _ := s.Attr("rel")
+		if !strings.Contains(strings.ToLower(rel), "alternate") {
+			return
+		}
+		typ, _ := s.Attr("type")
+		typ = strings.ToLower(strings.TrimSpace(typ))
+		if !slices.Contains(feedTypes, typ) {
+			return
+		}
+
+		href, _ := s.Attr("href")
+		u, err := neturl.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return
+		}
+		if base != nil {
+			u = base.ResolveReference(u)
+		}
+		if link := u.String(); !slices.Contains(links, link) {
+			links = append(links, link)
+		}
+	})
+	return links, nil
+}
@@ -0,0 +1,108 @@
+package rss
+
+import (
+	neturl "net/url"
+	"slices"
+	"testing"
+)
+
+func Test_normaliseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "full URL",
+			url:  "https://example.com/feed.xml",
+			want: "https://example.com/feed.xml",
+		},
+		{
+			name: "no scheme",
+			url:  " example.com/blog ",
+			want: "https://example.com/blog",
+		},
+		{
+			name: "http kept",
+			url:  "http://example.com",
+			want: "http://example.com",
+		},
+		{
+			name:    "empty",
+			url:     "  ",
+			wantErr: true,
+		},
+		{
+			name:    "other scheme",
+			url:     "ftp://example.com/feed",
+			wantErr: true,
+		},
+		{
+			name:    "no host",
+			url:     "https://",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normaliseURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normaliseURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normaliseURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_feedLinks(t *testing.T) {
+	base, _ := neturl.Parse("https://example.com/blog/")
+
+	tests := []struct {
+		name string
+		page string
+		want []string
+	}{
+		{
+			name: "rss and atom",
+			page: `<html><head>
+				<link rel="alternate" type="application/rss+xml" href="/rss.xml">
+				<link rel="alternate" type="application/atom+xml" href="https://example.com/atom.xml">
+			</head></html>`,
+			want: []string{"https://example.com/rss.xml", "https://example.com/atom.xml"},
+		},
+		{
+			name: "relative to the page",
+			page: `<link rel="alternate" type="application/feed+json" href="feed.json">`,
+			want: []string{"https://example.com/blog/feed.json"},
+		},
+		{
+			name: "other links skipped",
+			page: `<link rel="stylesheet" type="text/css" href="/style.css">
+				<link rel="alternate" hreflang="de" href="/de/">
+				<link rel="icon" type="application/rss+xml" href="/not-a-feed">`,
+			want: nil,
+		},
+		{
+			name: "duplicates",
+			page: `<link rel="alternate" type="application/rss+xml" href="/rss.xml">
+				<link rel="alternate" type="APPLICATION/RSS+XML" href="https://example.com/rss.xml">`,
+			want: []string{"https://example.com/rss.xml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := feedLinks([]byte(tt.page), base)
+			if err != nil {
+				t.Fatalf("feedLinks() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("feedLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,14 @@
 package rss
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mmcdole/gofeed"
@@ -12,30 +19,128 @@ import (
 type Feed struct {
 	URL      string
 	Title    string
+	Link     string // the site the feed belongs to
 	Articles []Article
 	Error    error
+	Status   int       // HTTP status of the last fetch, 0 when there was no response
+	Fetched  time.Time // when the feed last loaded, zero if it failed
 }
 
 type Article struct {
 	Title       string
 	Link        string
 	Description string
+	Content     string // full HTML from content:encoded / atom content, empty for summary-only feeds
 	Published   time.Time
+	Updated     time.Time
 	GUID        string
 	FeedTitle   string
+	Authors     []string
+	Categories  []string
+	Enclosures  []Enclosure
+	Image       string        // featured image URL
+	Comments    string        // comments page URL
+	Duration    time.Duration // podcast episode length, 0 if unknown
 }
 
-// fetches and parses an RSS feed from the given URL
+// a file attached to an article, e.g. a podcast episode
+type Enclosure struct {
+	URL    string
+	Type   string // MIME type
+	Length int64  // size in bytes, 0 if unknown
+}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// returned when the server answers with anything but success
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("server returned status %d", e.Code)
+}
+
+// fetches and parses an RSS, Atom or JSON feed from the given URL
 func FetchFeed(url string) (*Feed, error) {
-	fp := gofeed.NewParser()
-	feed, err := fp.ParseURL(url)
+	resp, err := fetch(url)
+	var feed *gofeed.Feed
+	if err == nil {
+		feed, err = parseFeed(resp.body, resp.contentType)
+	}
 	if err != nil {
-		return &Feed{
+		f := &Feed{
 			URL:   url,
 			Error: err,
-		}, err
+		}
+		if resp != nil {
+			f.Status = resp.status
+		}
+		return f, err
+	}
+
+	f := newFeed(url, feed)
+	f.Status = resp.status
+	f.Fetched = time.Now()
+	return f, nil
+}
+
+// a downloaded feed or page
+type response struct {
+	body        []byte
+	contentType string
+	url         *neturl.URL // where it was served from after redirects
+	status      int
+}
+
+// downloads a feed. the response is returned along with a *StatusError
+// when the server doesn't answer with success
+func fetch(url string) (*response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("User-Agent", "ohnurr")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, application/json;q=0.9, */*;q=0.8")
 
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	r := &response{
+		contentType: resp.Header.Get("Content-Type"),
+		url:         resp.Request.URL,
+		status:      resp.StatusCode,
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return r, &StatusError{Code: resp.StatusCode}
+	}
+
+	r.body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return r, fmt.Errorf("failed to read feed: %w", err)
+	}
+	return r, nil
+}
+
+// parses a feed, using the content type to pick JSON Feed and
+// falling back to sniffing the body for everything else
+func parseFeed(body []byte, contentType string) (*gofeed.Feed, error) {
+	if isJSONContentType(contentType) {
+		return parseJSONFeed(body)
+	}
+
+	fp := gofeed.NewParser()
+	fp.RSSTranslator = &rssTranslator{}
+	fp.AtomTranslator = &atomTranslator{}
+	fp.JSONTranslator = &jsonTranslator{}
+	return fp.Parse(bytes.NewReader(body))
+}
+
+// converts a parsed feed into our own types
+func newFeed(url string, feed *gofeed.Feed) *Feed {
 	articles := make([]Article, 0, len(feed.Items))
 	for _, item := range feed.Items {
 		guid := item.GUID
@@ -43,28 +148,126 @@ func FetchFeed(url string) (*Feed, error) {
 			guid = item.Link
 		}
 
-		var published time.Time
+		var published, updated time.Time
+		if item.UpdatedParsed != nil {
+			updated = *item.UpdatedParsed
+		}
 		if item.PublishedParsed != nil {
 			published = *item.PublishedParsed
-		} else if item.UpdatedParsed != nil {
-			published = *item.UpdatedParsed
+		} else {
+			published = updated
+		}
+
+		// summary falls back to the full content for feeds without descriptions
+		description := item.Description
+		if description == "" {
+			description = item.Content
 		}
 
 		articles = append(articles, Article{
 			Title:       item.Title,
 			Link:        item.Link,
-			Description: content.StripHTML(item.Description),
+			Description: content.StripHTML(description),
+			Content:     item.Content,
 			Published:   published,
+			Updated:     updated,
 			GUID:        guid,
 			FeedTitle:   feed.Title,
+			Authors:     itemAuthors(item),
+			Categories:  item.Categories,
+			Enclosures:  itemEnclosures(item),
+			Image:       itemImage(item),
+			Comments:    item.Custom[commentsKey],
+			Duration:    itemDuration(item),
 		})
 	}
 
 	return &Feed{
 		URL:      url,
 		Title:    feed.Title,
+		Link:     feed.Link,
 		Articles: articles,
-	}, nil
+	}
+}
+
+// returns author names, falling back to emails when there's no name
+func itemAuthors(item *gofeed.Item) []string {
+	people := item.Authors
+	if len(people) == 0 && item.Author != nil {
+		people = []*gofeed.Person{item.Author}
+	}
+
+	var authors []string
+	for _, p := range people {
+		if p == nil {
+			continue
+		}
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			name = strings.TrimSpace(p.Email)
+		}
+		if name != "" && !slices.Contains(authors, name) {
+			authors = append(authors, name)
+		}
+	}
+	return authors
+}
+
+func itemEnclosures(item *gofeed.Item) []Enclosure {
+	var enclosures []Enclosure
+	for _, e := range item.Enclosures {
+		if e == nil || e.URL == "" {
+			continue
+		}
+		length, _ := strconv.ParseInt(strings.TrimSpace(e.Length), 10, 64)
+		enclosures = append(enclosures, Enclosure{
+			URL:    e.URL,
+			Type:   e.Type,
+			Length: length,
+		})
+	}
+	return enclosures
+}
+
+// returns the featured image, falling back to the first image enclosure
+func itemImage(item *gofeed.Item) string {
+	if item.Image != nil && item.Image.URL != "" {
+		return item.Image.URL
+	}
+	for _, e := range item.Enclosures {
+		if e != nil && strings.HasPrefix(e.Type, "image/") {
+			return e.URL
+		}
+	}
+	return ""
+}
+
+// parses itunes:duration (seconds or [HH:]MM:SS), or the JSON Feed attachment duration
+func itemDuration(item *gofeed.Item) time.Duration {
+	if d, ok := item.Custom[durationKey]; ok {
+		return parseDuration(d)
+	}
+	if item.ITunesExt == nil {
+		return 0
+	}
+	return parseDuration(item.ITunesExt.Duration)
+}
+
+func parseDuration(s string) time.Duration {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+
+	var total int
+	for part := range strings.SplitSeq(s, ":") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0
+		}
+		total = total*60 + n
+	}
+	return time.Duration(total) * time.Second
 }
 
 // fetch multiple RSS feeds concurrently
@@ -76,14 +279,9 @@ func FetchAllFeeds(urls []string) []*Feed {
 		go func(index int, url string) {
 			feed, err := FetchFeed(url)
 			if err != nil {
-				results[index] = &Feed{
-					URL:   url,
-					Title: fmt.Sprintf("Error loading feed: %s", url),
-					Error: err,
-				}
-			} else {
-				results[index] = feed
+				feed.Title = fmt.Sprintf("Error loading feed: %s", url)
 			}
+			results[index] = feed
 			done <- true
 		}(i, url)
 	}
@@ -96,6 +294,16 @@ func FetchAllFeeds(urls []string) []*Feed {
 	return results
 }
 
+// returns the first audio or video attachment, nil if there is none
+func (a *Article) MediaEnclosure() *Enclosure {
+	for i, e := range a.Enclosures {
+		if strings.HasPrefix(e.Type, "audio/") || strings.HasPrefix(e.Type, "video/") {
+			return &a.Enclosures[i]
+		}
+	}
+	return nil
+}
+
 // returns a unique identifier for an article
 func (a *Article) GetArticleID() string {
 	if a.GUID != "" {
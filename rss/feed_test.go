@@ -0,0 +1,54 @@
+package rss
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_parseDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want time.Duration
+	}{
+		{
+			name: "seconds",
+			s:    "3600",
+			want: time.Hour,
+		},
+		{
+			name: "minutes and seconds",
+			s:    "45:30",
+			want: 45*time.Minute + 30*time.Second,
+		},
+		{
+			name: "hours minutes and seconds",
+			s:    "01:02:03",
+			want: time.Hour + 2*time.Minute + 3*time.Second,
+		},
+		{
+			name: "whitespace",
+			s:    " 90 ",
+			want: 90 * time.Second,
+		},
+		{
+			name: "empty",
+			s:    "",
+			want: 0,
+		},
+		{
+			name: "garbage",
+			s:    "about an hour",
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDuration(tt.s)
+			if got != tt.want {
+				t.Errorf("parseDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
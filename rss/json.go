@@ -0,0 +1,120 @@
+package rss
+
+import (
+	"bytes"
+	"html"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/json"
+)
+
+// key under which a JSON Feed attachment's duration (in seconds) is stored in gofeed.Item.Custom
+const durationKey = "ohnurr:duration"
+
+// reports whether a Content-Type header announces a JSON Feed
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/feed+json" || mediaType == "application/json"
+}
+
+// parses a JSON Feed (1.0 or 1.1)
+func parseJSONFeed(body []byte) (*gofeed.Feed, error) {
+	jp := &json.Parser{}
+	feed, err := jp.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return (&jsonTranslator{}).Translate(feed)
+}
+
+// fills in the JSON Feed bits the default translator leaves out
+type jsonTranslator struct {
+	gofeed.DefaultJSONTranslator
+}
+
+func (t *jsonTranslator) Translate(feed interface{}) (*gofeed.Feed, error) {
+	result, err := t.DefaultJSONTranslator.Translate(feed)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonFeed, ok := feed.(*json.Feed)
+	if !ok {
+		return result, nil
+	}
+
+	// items are translated in order so indexes line up
+	for i, jsonItem := range jsonFeed.Items {
+		if i >= len(result.Items) {
+			break
+		}
+		item := result.Items[i]
+
+		// content_text is plain text, not HTML
+		if jsonItem.ContentHTML == "" && jsonItem.ContentText != "" {
+			item.Content = textToHTML(jsonItem.ContentText)
+		}
+
+		// items without authors inherit the feed's
+		if len(item.Authors) == 0 && item.Author == nil {
+			item.Authors = result.Authors
+		}
+
+		// link blogs may only have an external URL
+		if item.Link == "" {
+			item.Link = jsonItem.ExternalURL
+		}
+
+		if item.Image == nil && jsonItem.BannerImage != "" {
+			item.Image = &gofeed.Image{URL: jsonItem.BannerImage}
+		}
+
+		if jsonItem.Attachments != nil {
+			item.Enclosures = translateAttachments(item, *jsonItem.Attachments)
+		}
+	}
+
+	return result, nil
+}
+
+// the default translator uses duration_in_seconds as the enclosure length,
+// so rebuild enclosures from size_in_bytes and keep the duration separately
+func translateAttachments(item *gofeed.Item, attachments []json.Attachments) []*gofeed.Enclosure {
+	enclosures := make([]*gofeed.Enclosure, 0, len(attachments))
+	for _, a := range attachments {
+		enclosure := &gofeed.Enclosure{
+			URL:  a.URL,
+			Type: a.MimeType,
+		}
+		if a.SizeInBytes > 0 {
+			enclosure.Length = strconv.FormatInt(a.SizeInBytes, 10)
+		}
+		enclosures = append(enclosures, enclosure)
+
+		if _, ok := item.Custom[durationKey]; !ok && a.DurationInSeconds > 0 {
+			setCustom(item, durationKey, strconv.FormatInt(a.DurationInSeconds, 10))
+		}
+	}
+	return enclosures
+}
+
+// wraps plain text in paragraphs so it renders like any other content
+func textToHTML(text string) string {
+	var b strings.Builder
+	for para := range strings.SplitSeq(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(para), "\n", "<br>"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
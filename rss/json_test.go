@@ -0,0 +1,94 @@
+package rss
+
+import (
+	"testing"
+	"time"
+)
+
+const testJSONFeed = `{
+	"version": "https://jsonfeed.org/version/1.1",
+	"title": "Example Blog",
+	"home_page_url": "https://example.com/",
+	"authors": [{"name": "Jane Doe"}],
+	"items": [
+		{
+			"id": "1",
+			"url": "https://example.com/posts/1",
+			"title": "First post",
+			"content_html": "<p>Hello <b>world</b></p>",
+			"date_published": "2025-01-02T10:00:00Z",
+			"date_modified": "2025-01-03T10:00:00Z",
+			"tags": ["go", "rss"],
+			"attachments": [
+				{"url": "https://example.com/ep1.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 1024, "duration_in_seconds": 90}
+			]
+		},
+		{
+			"id": "2",
+			"external_url": "https://elsewhere.com/story",
+			"content_text": "Just text\n\nTwo paragraphs & more",
+			"authors": [{"name": "John Smith"}]
+		}
+	]
+}`
+
+func TestParseJSONFeed(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+	}{
+		{name: "by content type", contentType: "application/feed+json; charset=utf-8"},
+		{name: "by shape", contentType: "text/plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseFeed([]byte(testJSONFeed), tt.contentType)
+			if err != nil {
+				t.Fatalf("parseFeed() error = %v", err)
+			}
+
+			feed := newFeed("https://example.com/feed.json", parsed)
+			if feed.Title != "Example Blog" {
+				t.Errorf("Title = %q, want %q", feed.Title, "Example Blog")
+			}
+			if len(feed.Articles) != 2 {
+				t.Fatalf("got %d articles, want 2", len(feed.Articles))
+			}
+
+			first := feed.Articles[0]
+			if first.Content != "<p>Hello <b>world</b></p>" {
+				t.Errorf("Content = %q", first.Content)
+			}
+			if first.Description != "Hello world" {
+				t.Errorf("Description = %q, want %q", first.Description, "Hello world")
+			}
+			if len(first.Authors) != 1 || first.Authors[0] != "Jane Doe" {
+				t.Errorf("Authors = %v, want [Jane Doe]", first.Authors)
+			}
+			if !first.Updated.Equal(time.Date(2025, 1, 3, 10, 0, 0, 0, time.UTC)) {
+				t.Errorf("Updated = %v", first.Updated)
+			}
+			if len(first.Categories) != 2 {
+				t.Errorf("Categories = %v, want [go rss]", first.Categories)
+			}
+			if len(first.Enclosures) != 1 || first.Enclosures[0].Type != "audio/mpeg" || first.Enclosures[0].Length != 1024 {
+				t.Errorf("Enclosures = %+v", first.Enclosures)
+			}
+			if first.Duration != 90*time.Second {
+				t.Errorf("Duration = %v, want 1m30s", first.Duration)
+			}
+
+			second := feed.Articles[1]
+			if second.Link != "https://elsewhere.com/story" {
+				t.Errorf("Link = %q, want external url", second.Link)
+			}
+			if second.Content != "<p>Just text</p>\n<p>Two paragraphs &amp; more</p>\n" {
+				t.Errorf("Content = %q", second.Content)
+			}
+			if len(second.Authors) != 1 || second.Authors[0] != "John Smith" {
+				t.Errorf("Authors = %v, want [John Smith]", second.Authors)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package rss
+
+import (
+	"github.com/mmcdole/gofeed"
+	"github.com/mmcdole/gofeed/atom"
+	gofeedrss "github.com/mmcdole/gofeed/rss"
+)
+
+// key under which the comments link is stored in gofeed.Item.Custom,
+// gofeed's universal item has no field for it
+const commentsKey = "ohnurr:comments"
+
+// keeps the rss <comments> element the default translator drops
+type rssTranslator struct {
+	gofeed.DefaultRSSTranslator
+}
+
+func (t *rssTranslator) Translate(feed interface{}) (*gofeed.Feed, error) {
+	result, err := t.DefaultRSSTranslator.Translate(feed)
+	if err != nil {
+		return nil, err
+	}
+
+	rssFeed, ok := feed.(*gofeedrss.Feed)
+	if !ok {
+		return result, nil
+	}
+
+	// items are translated in order so indexes line up
+	for i, item := range rssFeed.Items {
+		if i < len(result.Items) && item.Comments != "" {
+			setCustom(result.Items[i], commentsKey, item.Comments)
+		}
+	}
+
+	return result, nil
+}
+
+// keeps the atom rel="replies" link the default translator drops
+type atomTranslator struct {
+	gofeed.DefaultAtomTranslator
+}
+
+func (t *atomTranslator) Translate(feed interface{}) (*gofeed.Feed, error) {
+	result, err := t.DefaultAtomTranslator.Translate(feed)
+	if err != nil {
+		return nil, err
+	}
+
+	atomFeed, ok := feed.(*atom.Feed)
+	if !ok {
+		return result, nil
+	}
+
+	for i, entry := range atomFeed.Entries {
+		if i >= len(result.Items) {
+			break
+		}
+		for _, link := range entry.Links {
+			if link.Rel == "replies" && link.Href != "" {
+				setCustom(result.Items[i], commentsKey, link.Href)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func setCustom(item *gofeed.Item, key, value string) {
+	if item.Custom == nil {
+		item.Custom = make(map[string]string)
+	}
+	item.Custom[key] = value
+}
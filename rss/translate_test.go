@@ -0,0 +1,76 @@
+package rss
+
+import "testing"
+
+const testRSSFeed = `<?xml version="1.0"?>
+<rss version="2.0">
+	<channel>
+		<title>Example Blog</title>
+		<item>
+			<title>With comments</title>
+			<link>https://example.com/posts/1</link>
+			<comments>https://example.com/posts/1#comments</comments>
+		</item>
+		<item>
+			<title>Without comments</title>
+			<link>https://example.com/posts/2</link>
+		</item>
+	</channel>
+</rss>`
+
+const testAtomFeed = `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+	<title>Example Blog</title>
+	<entry>
+		<title>With comments</title>
+		<id>1</id>
+		<link href="https://example.com/posts/1"/>
+		<link rel="replies" href="https://example.com/posts/1/replies"/>
+	</entry>
+	<entry>
+		<title>Without comments</title>
+		<id>2</id>
+		<link href="https://example.com/posts/2"/>
+	</entry>
+</feed>`
+
+func TestParseFeed_comments(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		want        []string
+	}{
+		{
+			name:        "rss comments element",
+			body:        testRSSFeed,
+			contentType: "application/rss+xml",
+			want:        []string{"https://example.com/posts/1#comments", ""},
+		},
+		{
+			name:        "atom replies link",
+			body:        testAtomFeed,
+			contentType: "application/atom+xml",
+			want:        []string{"https://example.com/posts/1/replies", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseFeed([]byte(tt.body), tt.contentType)
+			if err != nil {
+				t.Fatalf("parseFeed() error = %v", err)
+			}
+
+			feed := newFeed("https://example.com/feed", parsed)
+			if len(feed.Articles) != len(tt.want) {
+				t.Fatalf("got %d articles, want %d", len(feed.Articles), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got := feed.Articles[i].Comments; got != want {
+					t.Errorf("Articles[%d].Comments = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
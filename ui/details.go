@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/pkg/browser"
+
+	"ohnurr/rss"
+)
+
+// widest the feed details box gets, wider terminals leave space around it
+const detailsMaxWidth = 90
+
+// returns the feed shown in the details panel, nil when it's closed
+func (m Model) detailsFeed() *rss.Feed {
+	if m.detailsURL == "" {
+		return nil
+	}
+	if i := m.feedIndex(m.detailsURL); i >= 0 {
+		return m.feeds[i]
+	}
+	return nil
+}
+
+// renders the details of a feed, mostly for finding out why it fails to load
+func (m Model) renderFeedDetails() string {
+	feed := m.detailsFeed()
+	width := max(min(m.width-4, detailsMaxWidth)-helpBoxStyle.GetHorizontalFrameSize(), 20)
+
+	title := feed.Title
+	if feed.Error != nil {
+		title = "❌ " + title
+	}
+
+	status := "-"
+	switch {
+	case feed.Status != 0:
+		status = fmt.Sprintf("%d %s", feed.Status, http.StatusText(feed.Status))
+	case feed.Error != nil:
+		status = "no response"
+	}
+
+	fetched := "never"
+	if !feed.Fetched.IsZero() {
+		fetched = feed.Fetched.Format("Jan 2, 2006 15:04") + " (" + formatPublishDate(feed.Fetched) + ")"
+	}
+
+	items := fmt.Sprintf("%d", len(feed.Articles))
+	if unread := m.GetUnreadCount(feed); unread > 0 {
+		items += fmt.Sprintf(" (%d unread)", unread)
+	}
+
+	rows := []struct{ label, value string }{
+		{"URL", feed.URL},
+		{"Site", feedSite(feed)},
+		{"Status", status},
+		{"Last fetch", fetched},
+		{"Items", items},
+	}
+	if folder := m.config.FeedMeta[feed.URL].Folder; folder != "" {
+		rows = append(rows, struct{ label, value string }{"Folder", folder})
+	}
+
+	const labelWidth = 12
+	valueStyle := lg.NewStyle().Width(width - labelWidth)
+	lines := []string{headerStyle.Render(truncate(title, width-2)), ""}
+	for _, r := range rows {
+		lines = append(lines, lg.JoinHorizontal(lg.Top, dimStyle.Width(labelWidth).Render(r.label), valueStyle.Render(r.value)))
+	}
+	if feed.Error != nil {
+		lines = append(lines, "", dimStyle.Render("Error"), lg.NewStyle().Width(width).Render(feed.Error.Error()))
+	}
+
+	k := m.keys
+	lines = append(lines, "", dimStyle.Render(hints(hint("retry", k.RefreshFeed), hint("open site", k.OpenBrowser), "Esc: close")))
+
+	box := helpBoxStyle.Width(width + helpBoxStyle.GetHorizontalPadding()).Render(strings.Join(lines, "\n"))
+	return m.imageMode.Clear() + lg.Place(m.width, m.height-1, lg.Center, lg.Center, box)
+}
+
+func (m Model) handleFeedDetailsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	feed := m.detailsFeed()
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.FeedDetails), msg.String() == "esc":
+		m.detailsURL = ""
+
+	case key.Matches(msg, m.keys.RefreshFeed):
+		return m, m.RefreshFeed(feed)
+
+	case key.Matches(msg, m.keys.OpenBrowser):
+		return m, m.OpenFeedSite(feed)
+	}
+	return m, nil
+}
+
+// returns the site a feed belongs to, the feed's own host when the feed
+// doesn't say (or couldn't be loaded)
+func feedSite(feed *rss.Feed) string {
+	if feed.Link != "" {
+		return feed.Link
+	}
+	u, err := neturl.Parse(feed.URL)
+	if err != nil || u.Host == "" {
+		return feed.URL
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// opens the site a feed belongs to in the browser
+func (m *Model) OpenFeedSite(feed *rss.Feed) tea.Cmd {
+	if feed == nil {
+		return nil
+	}
+	if err := browser.OpenURL(feedSite(feed)); err != nil {
+		return m.SetStatusMessage("Failed to open browser")
+	}
+	return m.SetStatusMessage("Opened in browser")
+}
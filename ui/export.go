@@ -0,0 +1,32 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ohnurr/content"
+)
+
+// saves what the reader is showing as Markdown in the export dir
+func (m *Model) ExportCurrentArticle() tea.Cmd {
+	key, title := m.readerTarget()
+	articleHTML, ok := m.articleCache.HTML(key)
+	if !ok {
+		return m.SetStatusMessage("Article not loaded yet")
+	}
+
+	meta := content.Metadata{Title: title, URL: key}
+	if len(m.linkHistory) == 0 {
+		if article := m.GetCurrentArticle(); article != nil {
+			meta.Authors = article.Authors
+			meta.Feed = article.FeedTitle
+			meta.URL = article.Link
+			meta.Date = article.Published
+		}
+	}
+
+	path, err := content.SaveMarkdown(m.config.Settings.ExportDir, meta, articleHTML)
+	if err != nil {
+		return m.SetStatusMessage("Export failed: " + err.Error())
+	}
+	return m.SetStatusMessage("Exported to " + path)
+}
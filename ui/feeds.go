@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ohnurr/rss"
+)
+
+type feedDiscoveredMsg struct {
+	feed *rss.Feed
+	err  error
+}
+
+type feedLoadedMsg struct {
+	feed *rss.Feed
+}
+
+// creates a command to look for a feed at a URL typed in by the user
+func discoverFeed(url string) tea.Cmd {
+	return func() tea.Msg {
+		feed, err := rss.Discover(url)
+		return feedDiscoveredMsg{feed: feed, err: err}
+	}
+}
+
+// creates a command to fetch a single feed
+func loadFeed(url string) tea.Cmd {
+	return func() tea.Msg {
+		feed := rss.FetchAllFeeds([]string{url})[0]
+		return feedLoadedMsg{feed: feed}
+	}
+}
+
+// returns the index of the feed with the given URL in m.feeds, -1 if there's none
+func (m Model) feedIndex(url string) int {
+	return slices.IndexFunc(m.feeds, func(f *rss.Feed) bool { return f.URL == url })
+}
+
+// gives a feed the title it was renamed to, if any
+func (m Model) applyFeedMeta(feed *rss.Feed) {
+	title := m.config.FeedMeta[feed.URL].Title
+	if title == "" {
+		return
+	}
+	feed.Title = title
+	for i := range feed.Articles {
+		feed.Articles[i].FeedTitle = title
+	}
+}
+
+// returns the folder a feed is grouped under. folder names typed in a
+// different case are the same folder
+func (m Model) folderKey(feed *rss.Feed) string {
+	return strings.ToLower(m.config.FeedMeta[feed.URL].Folder)
+}
+
+// orders feeds the way sources lists them: feeds outside folders first, then
+// each folder by name. feeds keep their order within a folder
+func (m *Model) sortFeeds() {
+	sort.SliceStable(m.feeds, func(i, j int) bool {
+		return m.folderKey(m.feeds[i]) < m.folderKey(m.feeds[j])
+	})
+}
+
+// rebuilds the article list after feeds change, keeping a feed filter
+// pointed at the current copy of the feed
+func (m *Model) rebuildArticles() {
+	if m.filteredFeed != nil {
+		if i := m.feedIndex(m.filteredFeed.URL); i >= 0 {
+			m.filterByFeed(m.feeds[i])
+			return
+		}
+		m.filteredFeed = nil
+	}
+	m.buildArticles()
+}
+
+// moves the sources selection to the feed with the given URL
+func (m *Model) selectFeed(url string) {
+	if i := m.feedIndex(url); i >= 0 {
+		m.selectedSource = i
+	}
+}
+
+// looks for a feed at a URL, which is subscribed to once found
+func (m *Model) AddFeed(url string) tea.Cmd {
+	url = strings.TrimSpace(url)
+	if url == "" {
+		return nil
+	}
+	m.statusMessage = "Looking for a feed at " + url + "..."
+	return discoverFeed(url)
+}
+
+// subscribes to a feed found by AddFeed
+func (m *Model) subscribe(msg feedDiscoveredMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Error adding feed: %v", msg.err))
+	}
+
+	feed := msg.feed
+	if slices.Contains(m.config.Feeds, feed.URL) {
+		m.selectFeed(feed.URL)
+		return m.SetStatusMessage("Already subscribed to " + feed.Title)
+	}
+	if err := m.config.AddFeed(feed.URL); err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Error adding feed: %v", err))
+	}
+	if err := m.config.Save(); err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Error saving config: %v", err))
+	}
+
+	m.noteFetched([]*rss.Feed{feed})
+	m.feeds = append(m.feeds, feed)
+	m.sortFeeds()
+	m.rebuildArticles()
+	m.moveArticleSelection(0)
+	m.selectFeed(feed.URL)
+	return m.SetStatusMessage("Added feed: " + feed.Title)
+}
+
+// unsubscribes from a feed
+func (m *Model) RemoveFeed(url string) tea.Cmd {
+	title := url
+	i := m.feedIndex(url)
+	if i >= 0 {
+		title = m.feeds[i].Title
+	}
+
+	if err := m.config.RemoveFeed(url); err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Error removing feed: %v", err))
+	}
+	if err := m.config.Save(); err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Error saving config: %v", err))
+	}
+
+	if i >= 0 {
+		m.feeds = slices.Delete(m.feeds, i, i+1)
+	}
+	m.rebuildArticles()
+	m.moveArticleSelection(0)
+	m.moveSourceSelection(0)
+	return m.SetStatusMessage("Removed feed: " + title)
+}
+
+// shows a feed under a title of our choosing, an empty title fetches the
+// feed again for its own
+func (m *Model) RenameFeed(url, title string) tea.Cmd {
+	if err := m.config.RenameFeed(url, title); err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Error renaming feed: %v", err))
+	}
+	if err := m.config.Save(); err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Error saving config: %v", err))
+	}
+
+	i := m.feedIndex(url)
+	if i < 0 {
+		return nil
+	}
+	if m.config.FeedMeta[url].Title == "" {
+		m.statusMessage = "Restoring the feed's own title..."
+		return loadFeed(url)
+	}
+
+	m.applyFeedMeta(m.feeds[i])
+	m.rebuildArticles()
+	return m.SetStatusMessage("Renamed feed to " + m.feeds[i].Title)
+}
+
+// moves a feed into a folder of the sources view, an empty name takes it
+// out of its folder
+func (m *Model) MoveFeed(url, folder string) tea.Cmd {
+	if err := m.config.MoveFeed(url, folder); err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Error moving feed: %v", err))
+	}
+	if err := m.config.Save(); err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Error saving config: %v", err))
+	}
+
+	m.sortFeeds()
+	m.selectFeed(url)
+	if folder = m.config.FeedMeta[url].Folder; folder == "" {
+		return m.SetStatusMessage("Moved feed out of its folder")
+	}
+	return m.SetStatusMessage("Moved feed to " + folder)
+}
+
+// fetches a single feed again, e.g. to retry one that failed
+func (m *Model) RefreshFeed(feed *rss.Feed) tea.Cmd {
+	if feed == nil {
+		return nil
+	}
+	m.statusMessage = "Refreshing " + feed.Title + "..."
+	return loadFeed(feed.URL)
+}
+
+// remembers when feeds last loaded. feeds that failed keep the time, title,
+// site and articles from the last time they loaded
+func (m *Model) noteFetched(feeds []*rss.Feed) {
+	fetched := false
+	for _, feed := range feeds {
+		if feed.Error == nil {
+			m.state.SetFetched(feed.URL, feed.Fetched)
+			fetched = true
+			continue
+		}
+
+		feed.Fetched = m.state.LastFetched(feed.URL)
+		if i := m.feedIndex(feed.URL); i >= 0 {
+			old := m.feeds[i]
+			feed.Title, feed.Link, feed.Articles = old.Title, old.Link, old.Articles
+		}
+	}
+	if fetched {
+		_ = m.state.Save()
+	}
+}
+
+// remembers which articles of freshly loaded feeds weren't there before, to
+// highlight them until they're read. feeds loading for the first time have
+// nothing new
+func (m *Model) noteNewArticles(feeds []*rss.Feed) (count int) {
+	for _, feed := range feeds {
+		i := m.feedIndex(feed.URL)
+		if i < 0 || feed.Error != nil || (m.feeds[i].Error != nil && len(m.feeds[i].Articles) == 0) {
+			continue
+		}
+
+		seen := make(map[string]bool, len(m.feeds[i].Articles))
+		for _, a := range m.feeds[i].Articles {
+			seen[a.GetArticleID()] = true
+		}
+		for _, a := range feed.Articles {
+			if id := a.GetArticleID(); !seen[id] {
+				m.newArticles[id] = true
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// reports whether an article arrived with a refresh and hasn't been read since
+func (m Model) isNewArticle(article *rss.Article) bool {
+	return m.newArticles[article.GetArticleID()]
+}
+
+// returns the feed an article belongs to
+func (m Model) feedOf(article *rss.Article) *rss.Feed {
+	for _, feed := range m.feeds {
+		for i := range feed.Articles {
+			if &feed.Articles[i] == article {
+				return feed
+			}
+		}
+	}
+	return nil
+}
+
+// returns the feed selected in sources view, or the selected article's
+func (m Model) selectedFeed() *rss.Feed {
+	if m.currentView == sourcesView {
+		return m.GetCurrentSource()
+	}
+	if article := m.GetCurrentArticle(); article != nil {
+		return m.feedOf(article)
+	}
+	return nil
+}
+
+// swaps in a feed fetched on its own, merging its articles into the list
+// without moving the selection off the selected article
+func (m *Model) updateFeed(feed *rss.Feed) tea.Cmd {
+	i := m.feedIndex(feed.URL)
+	if i < 0 {
+		// removed while it was loading
+		return nil
+	}
+
+	selected := m.currentArticleID()
+	added := m.noteNewArticles([]*rss.Feed{feed})
+	m.noteFetched([]*rss.Feed{feed})
+	m.applyFeedMeta(feed)
+	m.feeds[i] = feed
+	m.rebuildArticles()
+	m.selectArticle(selected)
+
+	switch {
+	case feed.Error != nil:
+		return m.SetStatusMessage(fmt.Sprintf("Error loading %s: %v", feed.URL, feed.Error))
+	case added == 1:
+		return m.SetStatusMessage("Updated " + feed.Title + ": 1 new article")
+	case added > 0:
+		return m.SetStatusMessage(fmt.Sprintf("Updated %s: %d new articles", feed.Title, added))
+	}
+	return m.SetStatusMessage("Updated " + feed.Title + ", nothing new")
+}
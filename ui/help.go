@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// keys typed rather than bound, listed in the help of the views they work in
+var fixedHelp = []struct {
+	group, keys, desc string
+	scope             keyScope
+}{
+	{groupLinks, "0-9", "select link by number", scopeReader},
+}
+
+func (v viewMode) scope() keyScope {
+	switch v {
+	case articleView:
+		return scopeReader
+	case sourcesView:
+		return scopeSources
+	}
+	return scopeArticles
+}
+
+// returns the lines of the help overlay for the current view: every bound
+// action grouped under headings
+func (m Model) helpLines() []string {
+	type entry struct{ keys, desc string }
+	entries := make(map[string][]entry)
+
+	scope := m.currentView.scope()
+	for _, a := range m.keys.actions() {
+		if a.scope&scope != 0 && a.binding.Enabled() {
+			entries[a.group] = append(entries[a.group], entry{a.binding.Help().Key, a.binding.Help().Desc})
+		}
+	}
+	for _, f := range fixedHelp {
+		if f.scope&scope != 0 {
+			entries[f.group] = append(entries[f.group], entry{f.keys, f.desc})
+		}
+	}
+
+	keyWidth := 0
+	for _, group := range entries {
+		for _, e := range group {
+			keyWidth = max(keyWidth, lg.Width(e.keys))
+		}
+	}
+
+	var lines []string
+	for _, group := range keyGroups {
+		if len(entries[group]) == 0 {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, articleTitleStyle.Render(group))
+		for _, e := range entries[group] {
+			lines = append(lines, helpKeyStyle.Width(keyWidth+2).Render(e.keys)+e.desc)
+		}
+	}
+	return lines
+}
+
+// returns how many help lines fit on screen inside the box
+func (m Model) helpHeight() int {
+	return max(m.height-1-4, 1) // status bar, border and title
+}
+
+func (m Model) renderHelp() string {
+	lines := m.helpLines()
+	height := m.helpHeight()
+	start := min(m.helpScroll, max(len(lines)-height, 0))
+	end := min(start+height, len(lines))
+
+	title := headerStyle.Render("Keys") + dimStyle.Render("in the "+viewTitle(m.currentView))
+	if start > 0 {
+		title += dimStyle.Render(" ↑")
+	}
+	if end < len(lines) {
+		title += dimStyle.Render(" ↓")
+	}
+
+	// as wide as the widest line, so the box doesn't change size while scrolling
+	width := lg.Width(title)
+	for _, line := range lines {
+		width = max(width, lg.Width(line))
+	}
+	style := helpBoxStyle.Width(width + helpBoxStyle.GetHorizontalPadding())
+
+	box := style.Render(strings.Join(append([]string{title, ""}, lines[start:end]...), "\n"))
+	return m.imageMode.Clear() + lg.Place(m.width, m.height-1, lg.Center, lg.Center, box)
+}
+
+func viewTitle(v viewMode) string {
+	switch v {
+	case articleView:
+		return "reader"
+	case sourcesView:
+		return "sources"
+	}
+	return "articles"
+}
+
+func (m Model) handleHelpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxScroll := max(len(m.helpLines())-m.helpHeight(), 0)
+	m.helpScroll = min(m.helpScroll, maxScroll)
+
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Help), msg.String() == "esc":
+		m.showHelp = false
+
+	case key.Matches(msg, m.keys.Up):
+		m.helpScroll = max(m.helpScroll-1, 0)
+
+	case key.Matches(msg, m.keys.Down):
+		m.helpScroll = min(m.helpScroll+1, maxScroll)
+
+	case key.Matches(msg, m.keys.PageUp, m.keys.HalfPageUp):
+		m.helpScroll = max(m.helpScroll-m.helpHeight()/2, 0)
+
+	case key.Matches(msg, m.keys.PageDown, m.keys.HalfPageDown):
+		m.helpScroll = min(m.helpScroll+m.helpHeight()/2, maxScroll)
+
+	case key.Matches(msg, m.keys.Top):
+		m.helpScroll = 0
+
+	case key.Matches(msg, m.keys.Bottom):
+		m.helpScroll = maxScroll
+	}
+	return m, nil
+}
+
+// opens the help overlay for the current view
+func (m *Model) openHelp() {
+	m.showHelp = true
+	m.helpScroll = 0
+}
@@ -0,0 +1,350 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// KeyMap holds the keys bound to every action. defaults can be changed in
+// the settings file with "key.<action> = a, b", see keyAction names
+type KeyMap struct {
+	Quit        key.Binding
+	Help        key.Binding
+	Sources     key.Binding
+	Refresh     key.Binding
+	RefreshFeed key.Binding
+	Search      key.Binding
+	ClearSearch key.Binding
+	FocusLeft   key.Binding
+	FocusRight  key.Binding
+
+	// lists and reader
+	Up   key.Binding
+	Down key.Binding
+
+	// articles
+	Open        key.Binding
+	OpenBrowser key.Binding
+	ToggleRead  key.Binding
+	Podcasts    key.Binding
+	Play        key.Binding
+	Download    key.Binding
+	SaveSearch  key.Binding
+
+	// reader
+	Back         key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+	Top          key.Binding
+	Bottom       key.Binding
+	JumpPercent  key.Binding
+	NextArticle  key.Binding
+	PrevArticle  key.Binding
+	NextUnread   key.Binding
+	NextLink     key.Binding
+	PrevLink     key.Binding
+	OpenLink     key.Binding
+	ReadLink     key.Binding
+	Export       key.Binding
+
+	// sources
+	FeedDetails key.Binding
+	AddFeed     key.Binding
+	Delete      key.Binding
+	RenameFeed  key.Binding
+	MoveFeed    key.Binding
+	ShowAll     key.Binding
+}
+
+// views a binding applies in, keys may only be reused by actions that are
+// never active at the same time
+type keyScope uint8
+
+const (
+	scopeArticles keyScope = 1 << iota
+	scopeReader
+	scopeSources
+
+	scopeLists = scopeArticles | scopeSources
+	scopeAll   = scopeArticles | scopeReader | scopeSources
+)
+
+// headings actions are grouped under in the help overlay, in order
+const (
+	groupGeneral      = "General"
+	groupMovingAround = "Moving around"
+	groupArticles     = "Articles"
+	groupLinks        = "Links"
+	groupSearch       = "Search"
+	groupPodcasts     = "Podcasts"
+	groupSources      = "Sources"
+)
+
+var keyGroups = []string{
+	groupGeneral, groupMovingAround, groupArticles, groupLinks, groupSearch, groupPodcasts, groupSources,
+}
+
+// a binding with the name it's configured by
+type keyAction struct {
+	name    string
+	group   string
+	scope   keyScope
+	binding *key.Binding
+}
+
+func (k *KeyMap) actions() []keyAction {
+	return []keyAction{
+		{"quit", groupGeneral, scopeAll, &k.Quit},
+		{"help", groupGeneral, scopeAll, &k.Help},
+		{"sources", groupGeneral, scopeLists, &k.Sources},
+		{"refresh", groupGeneral, scopeAll, &k.Refresh},
+		{"refresh_feed", groupGeneral, scopeAll, &k.RefreshFeed},
+		{"search", groupSearch, scopeArticles, &k.Search},
+		{"clear_search", groupSearch, scopeArticles, &k.ClearSearch},
+		{"focus_left", groupGeneral, scopeAll, &k.FocusLeft},
+		{"focus_right", groupGeneral, scopeAll, &k.FocusRight},
+		{"up", groupMovingAround, scopeAll, &k.Up},
+		{"down", groupMovingAround, scopeAll, &k.Down},
+		{"open", groupArticles, scopeLists, &k.Open},
+		{"open_browser", groupArticles, scopeAll, &k.OpenBrowser},
+		{"toggle_read", groupArticles, scopeArticles, &k.ToggleRead},
+		{"podcasts", groupPodcasts, scopeArticles, &k.Podcasts},
+		{"play", groupPodcasts, scopeArticles | scopeReader, &k.Play},
+		{"download", groupPodcasts, scopeArticles | scopeReader, &k.Download},
+		{"save_search", groupSearch, scopeArticles, &k.SaveSearch},
+		{"back", groupMovingAround, scopeReader, &k.Back},
+		{"page_up", groupMovingAround, scopeReader, &k.PageUp},
+		{"page_down", groupMovingAround, scopeReader, &k.PageDown},
+		{"half_page_up", groupMovingAround, scopeReader, &k.HalfPageUp},
+		{"half_page_down", groupMovingAround, scopeReader, &k.HalfPageDown},
+		{"top", groupMovingAround, scopeReader, &k.Top},
+		{"bottom", groupMovingAround, scopeReader, &k.Bottom},
+		{"jump_percent", groupMovingAround, scopeReader, &k.JumpPercent},
+		{"next_article", groupArticles, scopeReader, &k.NextArticle},
+		{"prev_article", groupArticles, scopeReader, &k.PrevArticle},
+		{"next_unread", groupArticles, scopeReader, &k.NextUnread},
+		{"next_link", groupLinks, scopeReader, &k.NextLink},
+		{"prev_link", groupLinks, scopeReader, &k.PrevLink},
+		{"open_link", groupLinks, scopeReader, &k.OpenLink},
+		{"read_link", groupLinks, scopeReader, &k.ReadLink},
+		{"export", groupArticles, scopeReader, &k.Export},
+		{"feed_details", groupSources, scopeSources, &k.FeedDetails},
+		{"add_feed", groupSources, scopeSources, &k.AddFeed},
+		{"delete", groupSources, scopeSources, &k.Delete},
+		{"rename_feed", groupSources, scopeSources, &k.RenameFeed},
+		{"move_feed", groupSources, scopeSources, &k.MoveFeed},
+		{"show_all", groupSources, scopeSources, &k.ShowAll},
+	}
+}
+
+func binding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKeys(keys), desc))
+}
+
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Quit:        binding("quit", "q", "ctrl+c"),
+		Help:        binding("help", "?"),
+		Sources:     binding("toggle sources", "s"),
+		Refresh:     binding("refresh", "r"),
+		RefreshFeed: binding("refresh this feed", "R"),
+		Search:      binding("search", "/"),
+		ClearSearch: binding("clear search", "esc"),
+		FocusLeft:   binding("focus left pane", "left"),
+		FocusRight:  binding("focus right pane", "right"),
+
+		Up:   binding("up", "up", "k"),
+		Down: binding("down", "down", "j"),
+
+		Open:        binding("open selected", "enter"),
+		OpenBrowser: binding("open in browser", "o"),
+		ToggleRead:  binding("toggle read", "m"),
+		Podcasts:    binding("podcasts", "p"),
+		Play:        binding("play episode", "P"),
+		Download:    binding("download episode", "D"),
+		SaveSearch:  binding("save search", "S"),
+
+		Back:         binding("back", "esc"),
+		PageUp:       binding("page up", "pgup"),
+		PageDown:     binding("page down", "pgdown"),
+		HalfPageUp:   binding("half page up", "ctrl+u"),
+		HalfPageDown: binding("half page down", "ctrl+d"),
+		Top:          binding("top", "g"),
+		Bottom:       binding("bottom", "G"),
+		JumpPercent:  binding("jump to N%", "%"),
+		NextArticle:  binding("next article", "J"),
+		PrevArticle:  binding("previous article", "K"),
+		NextUnread:   binding("next unread", "n"),
+		NextLink:     binding("next link", "tab"),
+		PrevLink:     binding("previous link", "shift+tab"),
+		OpenLink:     binding("open link in browser", "enter"),
+		ReadLink:     binding("read link", "l"),
+		Export:       binding("export to Markdown", "e"),
+
+		FeedDetails: binding("feed details", "i"),
+		AddFeed:     binding("add feed", "n"),
+		Delete:      binding("remove feed or saved search", "d"),
+		RenameFeed:  binding("rename feed", "e"),
+		MoveFeed:    binding("move feed to folder", "m"),
+		ShowAll:     binding("show all feeds", "a"),
+	}
+}
+
+// old names of renamed actions, so settings files using them still load
+var actionAliases = map[string]string{
+	"delete_search": "delete",
+}
+
+// returns the default keymap with the bindings from the settings file,
+// action name -> comma separated keys. an empty list unbinds the action
+func LoadKeyMap(bindings map[string]string) (KeyMap, error) {
+	k := DefaultKeyMap()
+	actions := k.actions()
+
+	for name, value := range bindings {
+		if newName, ok := actionAliases[name]; ok {
+			if _, ok := bindings[newName]; ok {
+				// the new name wins
+				continue
+			}
+			name = newName
+		}
+
+		i := slices.IndexFunc(actions, func(a keyAction) bool { return a.name == name })
+		if i < 0 {
+			return k, fmt.Errorf("unknown action %q", name)
+		}
+
+		var keys []string
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				keys = append(keys, keyName(s))
+			}
+		}
+
+		b := actions[i].binding
+		b.SetKeys(keys...)
+		b.SetHelp(helpKeys(keys), b.Help().Desc)
+		b.SetEnabled(len(keys) > 0)
+	}
+
+	return k, k.conflicts()
+}
+
+// returns the key as bubbletea names it, for keys that are awkward to
+// write in the settings file
+func keyName(s string) string {
+	switch strings.ToLower(s) {
+	case "space":
+		return " "
+	case "comma":
+		return ","
+	}
+	return s
+}
+
+// reports whether the reader keeps a key for itself: digits type footnote
+// numbers and how far to jump through the article
+func reservedKey(k string) bool {
+	return len(k) == 1 && k[0] >= '0' && k[0] <= '9'
+}
+
+// returns an error for keys bound to more than one action in the same view,
+// or to a key the view keeps for itself
+func (k *KeyMap) conflicts() error {
+	var errs []string
+	actions := k.actions()
+	for i, a := range actions {
+		for _, bound := range a.binding.Keys() {
+			if a.scope&scopeReader != 0 && reservedKey(bound) {
+				errs = append(errs, fmt.Sprintf("%q is bound to %s but types numbers in the reader", bound, a.name))
+			}
+		}
+		for _, b := range actions[i+1:] {
+			if a.scope&b.scope == 0 {
+				continue
+			}
+			for _, shared := range a.binding.Keys() {
+				if slices.Contains(b.binding.Keys(), shared) {
+					errs = append(errs, fmt.Sprintf("%q is bound to both %s and %s", shared, a.name, b.name))
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("conflicting keybindings: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// names keys the way they're shown in help
+var keyLabels = map[string]string{
+	"up": "↑", "down": "↓", "left": "←", "right": "→",
+	"pgup": "PgUp", "pgdown": "PgDn", "esc": "Esc", "enter": "Enter", "tab": "Tab",
+	"shift+tab": "Shift+Tab", " ": "Space",
+}
+
+func keyLabel(k string) string {
+	if label, ok := keyLabels[k]; ok {
+		return label
+	}
+	if ctrl, ok := strings.CutPrefix(k, "ctrl+"); ok {
+		return "^" + ctrl
+	}
+	return k
+}
+
+func helpKeys(keys []string) string {
+	labels := make([]string, len(keys))
+	for i, k := range keys {
+		labels[i] = keyLabel(k)
+	}
+	return strings.Join(labels, "/")
+}
+
+// returns the keys of bindings for a status bar hint: "g/G" for single
+// keys, "↑↓/kj" when bindings have several keys to show them side by side
+func hintKeys(bindings ...key.Binding) string {
+	single := true
+	for _, b := range bindings {
+		if !b.Enabled() {
+			return ""
+		}
+		single = single && len(b.Keys()) == 1
+	}
+
+	var columns []string
+	for _, b := range bindings {
+		for i, k := range b.Keys() {
+			switch {
+			case single:
+				columns = append(columns, keyLabel(k))
+			case i == len(columns):
+				columns = append(columns, keyLabel(k))
+			default:
+				columns[i] += keyLabel(k)
+			}
+		}
+	}
+	return strings.Join(columns, "/")
+}
+
+// returns a hint for the status bar like "↑↓/kj: nav", "" when the action
+// is unbound
+func hint(desc string, bindings ...key.Binding) string {
+	keys := hintKeys(bindings...)
+	if keys == "" {
+		return ""
+	}
+	return keys + ": " + desc
+}
+
+// joins hints for the status bar, leaving out unbound actions
+func hints(h ...string) string {
+	return strings.Join(slices.DeleteFunc(h, func(s string) bool { return s == "" }), " | ")
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLoadKeyMap(t *testing.T) {
+	tests := []struct {
+		name     string
+		bindings map[string]string
+		want     func(KeyMap) bool
+		wantErr  bool
+	}{
+		{
+			name: "defaults",
+			want: func(k KeyMap) bool { return slices.Equal(k.Quit.Keys(), []string{"q", "ctrl+c"}) },
+		},
+		{
+			name:     "rebind",
+			bindings: map[string]string{"down": "j, down, ctrl+n"},
+			want:     func(k KeyMap) bool { return slices.Equal(k.Down.Keys(), []string{"j", "down", "ctrl+n"}) },
+		},
+		{
+			name:     "empty unbinds",
+			bindings: map[string]string{"next_unread": ""},
+			want:     func(k KeyMap) bool { return !k.NextUnread.Enabled() && len(k.NextUnread.Keys()) == 0 },
+		},
+		{
+			name:     "space and comma",
+			bindings: map[string]string{"page_down": "space, pgdown", "page_up": "Comma"},
+			want: func(k KeyMap) bool {
+				return slices.Equal(k.PageDown.Keys(), []string{" ", "pgdown"}) && slices.Equal(k.PageUp.Keys(), []string{","})
+			},
+		},
+		{
+			name:     "old action name",
+			bindings: map[string]string{"delete_search": "x"},
+			want:     func(k KeyMap) bool { return slices.Equal(k.Delete.Keys(), []string{"x"}) },
+		},
+		{
+			name:     "new name wins over the old one",
+			bindings: map[string]string{"delete_search": "x", "delete": "X"},
+			want:     func(k KeyMap) bool { return slices.Equal(k.Delete.Keys(), []string{"X"}) },
+		},
+		{
+			name:     "same key in different views",
+			bindings: map[string]string{"read_link": "s"},
+			want:     func(k KeyMap) bool { return slices.Equal(k.ReadLink.Keys(), []string{"s"}) },
+		},
+		{
+			name:     "digit outside the reader",
+			bindings: map[string]string{"add_feed": "1"},
+			want:     func(k KeyMap) bool { return slices.Equal(k.AddFeed.Keys(), []string{"1"}) },
+		},
+		{
+			name:     "unknown action",
+			bindings: map[string]string{"launch": "x"},
+			wantErr:  true,
+		},
+		{
+			name:     "conflicting pair",
+			bindings: map[string]string{"quit": "j"},
+			wantErr:  true,
+		},
+		{
+			name:     "reserved digit",
+			bindings: map[string]string{"quit": "1"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadKeyMap(tt.bindings)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadKeyMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !tt.want(got) {
+				t.Errorf("LoadKeyMap() = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// terminals at least this wide show sources, articles and the reader side by side
+const splitMinWidth = 160
+
+// how long the selection rests on an article before the reader pane loads
+// it, so moving through the list doesn't scrape every article passed
+const previewDelay = 150 * time.Millisecond
+
+type previewTickMsg struct {
+	seq int
+}
+
+// reports whether the screen is wide enough for the three pane layout
+func (m Model) splitLayout() bool {
+	return m.width >= splitMinWidth
+}
+
+// returns the outer widths of the sources, articles and reader panes
+func (m Model) paneWidths() (sources, articles, reader int) {
+	sources = min(max(m.width/6, 24), 36)
+	articles = min(max(m.width*3/10, 40), 64)
+	return sources, articles, m.width - sources - articles
+}
+
+// returns the size of the area the reader is drawn in, the whole screen or
+// the inside of its pane. like m.height, the height includes the status bar
+func (m Model) readerSize() (width, height int) {
+	if !m.splitLayout() {
+		return m.width, m.height
+	}
+	_, _, reader := m.paneWidths()
+	return reader - 2, m.height - 2 // border
+}
+
+// renders the sources, articles and reader panes next to each other, the
+// focused one (the current view) highlighted
+func (m Model) renderSplitView() string {
+	sourcesWidth, articlesWidth, readerWidth := m.paneWidths()
+
+	pane := func(width int, render func(Model) string) string {
+		return render(m.paneModel(width))
+	}
+
+	panes := []struct {
+		view    viewMode
+		width   int
+		content string
+	}{
+		{sourcesView, sourcesWidth, pane(sourcesWidth, Model.renderSourcesView)},
+		{articlesView, articlesWidth, pane(articlesWidth, Model.renderArticlesView)},
+		{articleView, readerWidth, m.renderArticleView()},
+	}
+
+	var rendered []string
+	for _, p := range panes {
+		style := paneStyle
+		if p.view == m.currentView {
+			style = focusedPaneStyle
+		}
+		rendered = append(rendered, style.Render(fitPane(p.content, p.width-2, m.height-3)))
+	}
+	return lg.JoinHorizontal(lg.Top, rendered...)
+}
+
+// returns the model as the list views inside a pane of the split layout
+// see it: laid out for a screen the size of the pane's inside
+func (m Model) paneModel(width int) Model {
+	p := m
+	p.width, p.height = width-2, m.height-2 // border
+	return p
+}
+
+// returns the view of the pane at screen column x, and where x and y are
+// inside it. without the split layout the current view fills the screen
+func (m Model) paneAt(x, y int) (view viewMode, paneX, paneY int) {
+	if !m.splitLayout() {
+		return m.currentView, x, y
+	}
+
+	sources, articles, _ := m.paneWidths()
+	switch {
+	case x < sources:
+		return sourcesView, x - 1, y - 1
+	case x < sources+articles:
+		return articlesView, x - sources - 1, y - 1
+	}
+	return articleView, x - sources - articles - 1, y - 1
+}
+
+// cuts or pads content to exactly width columns and height lines
+func fitPane(content string, width, height int) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	for i, line := range lines {
+		line = ansi.Truncate(line, width, "…")
+		lines[i] = line + strings.Repeat(" ", max(width-lg.Width(line), 0))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// moves focus to the pane left (delta -1) or right (delta 1) of the current one
+func (m *Model) moveFocus(delta int) tea.Cmd {
+	switch {
+	case m.currentView == sourcesView && delta > 0:
+		m.currentView = articlesView
+	case m.currentView == articlesView && delta < 0:
+		m.currentView = sourcesView
+	case m.currentView == articlesView && delta > 0:
+		return m.openArticle()
+	case m.currentView == articleView && delta < 0:
+		// the reader goes back to previewing the selected article
+		m.saveArticleScroll()
+		m.linkHistory = nil
+		m.clearLinkSelection()
+		m.currentView = articlesView
+		m.restoreArticleScroll()
+	}
+	return nil
+}
+
+// previews the selected article right away when it's at hand, otherwise
+// once the selection has stayed on it for previewDelay
+func (m *Model) schedulePreview() tea.Cmd {
+	article := m.GetCurrentArticle()
+	if article == nil {
+		return nil
+	}
+
+	m.previewSeq++
+	if article.Content != "" || m.articleCache.Has(articleCacheKey(article)) {
+		return m.previewArticle()
+	}
+
+	m.restoreArticleScroll()
+	m.loadingArticle = true
+	seq := m.previewSeq
+	return tea.Tick(previewDelay, func(time.Time) tea.Msg {
+		return previewTickMsg{seq: seq}
+	})
+}
+
+// shows the selected article in the reader pane without opening it
+func (m *Model) previewArticle() tea.Cmd {
+	if m.GetCurrentArticle() == nil {
+		return nil
+	}
+	m.restoreArticleScroll()
+	return m.loadCurrentArticle()
+}
@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pkg/browser"
+
+	"ohnurr/content"
+)
+
+// returns the cache key and title of what the reader is showing:
+// the last link opened in the reader, or the selected article
+func (m Model) readerTarget() (key, title string) {
+	if n := len(m.linkHistory); n > 0 {
+		link := m.linkHistory[n-1]
+		return link.URL, link.Text
+	}
+
+	article := m.GetCurrentArticle()
+	if article == nil {
+		return "", ""
+	}
+	return articleCacheKey(article), article.Title
+}
+
+// returns the rendered content shown in the reader, nil if it isn't loaded yet
+func (m Model) readerContent() *content.RenderedArticle {
+	key, _ := m.readerTarget()
+	if key == "" {
+		return nil
+	}
+	rendered, _ := m.articleCache.Get(key, m.readerWidth())
+	return rendered
+}
+
+// returns the footnote links of the content in the reader
+func (m Model) readerLinks() []content.Link {
+	if rendered := m.readerContent(); rendered != nil {
+		return rendered.Links
+	}
+	return nil
+}
+
+// returns the selected footnote link, nil if none is selected
+func (m Model) currentLink() *content.Link {
+	links := m.readerLinks()
+	if m.selectedLink < 1 || m.selectedLink > len(links) {
+		return nil
+	}
+	return &links[m.selectedLink-1]
+}
+
+// moves the link selection by delta, wrapping around
+func (m *Model) cycleLink(delta int) {
+	count := len(m.readerLinks())
+	if count == 0 {
+		return
+	}
+	m.linkDigits = ""
+	m.selectedLink = ((m.selectedLink-1+delta)%count+count)%count + 1
+}
+
+// selects a link by typing its footnote number, digit by digit
+func (m *Model) selectLinkDigit(digit string) {
+	count := len(m.readerLinks())
+	if count == 0 {
+		return
+	}
+
+	n, _ := strconv.Atoi(m.linkDigits + digit)
+	if n < 1 || n > count {
+		// start over from this digit
+		m.linkDigits = ""
+		n, _ = strconv.Atoi(digit)
+		if n < 1 || n > count {
+			return
+		}
+	}
+
+	m.linkDigits += digit
+	m.selectedLink = n
+}
+
+func (m *Model) clearLinkSelection() {
+	m.selectedLink = 0
+	m.linkDigits = ""
+}
+
+// opens the selected link in the browser
+func (m *Model) OpenCurrentLink() tea.Cmd {
+	link := m.currentLink()
+	if link == nil {
+		return nil
+	}
+	if err := browser.OpenURL(link.URL); err != nil {
+		return m.SetStatusMessage("Failed to open browser")
+	}
+	return m.SetStatusMessage("Opened in browser")
+}
+
+// loads the selected link in the reader, esc goes back to where we came from
+func (m *Model) ReadCurrentLink() tea.Cmd {
+	link := m.currentLink()
+	if link == nil {
+		return nil
+	}
+
+	m.saveArticleScroll()
+	m.linkHistory = append(m.linkHistory, *link)
+	m.clearLinkSelection()
+	m.restoreArticleScroll()
+
+	m.loadingArticle = !m.articleCache.Has(link.URL)
+	if !m.loadingArticle {
+		return nil
+	}
+	return loadLinkContent(m.articleCache, link.URL)
+}
+
+// creates a command to scrape a link into the cache
+func loadLinkContent(cache *content.Cache, url string) tea.Cmd {
+	return func() tea.Msg {
+		err := cache.Load(url)
+		return articleContentLoadedMsg{
+			key: url,
+			err: err,
+		}
+	}
+}
+
+// describes the selected link for the status bar
+func (m Model) linkStatus() string {
+	link := m.currentLink()
+	if link == nil {
+		return ""
+	}
+	text := truncate(link.Text, 30)
+	actions := hints(hint("browser", m.keys.OpenLink), hint("reader", m.keys.ReadLink), hint("clear", m.keys.Back))
+	return fmt.Sprintf("[%d/%d] %s → %s | %s", m.selectedLink, len(m.readerLinks()), text, link.URL, actions)
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"fmt"
+	"hash/fnv"
 	"sort"
 	"time"
 
@@ -20,26 +22,46 @@ const (
 )
 
 type Model struct {
-	config               *config.Config
-	state                *config.State
-	feeds                []*rss.Feed
-	allArticles          []articleWithSource
-	selectedArticle      int
-	selectedSource       int
-	currentView          viewMode
-	filteredFeed         *rss.Feed // nil == show all feeds
-	searchInputTrap      bool
-	searchQuery          string
-	width                int
-	height               int
-	loading              bool
-	statusMessage        string
-	articleScroll        int // scroll position in article view
-	cachedArticleURL     string
-	cachedArticleContent string // TODO: cache more than one article at a time
-	loadingArticle       bool
+	config          *config.Config
+	state           *config.State
+	feeds           []*rss.Feed
+	allArticles     []articleWithSource
+	selectedArticle int
+	selectedSource  int
+	currentView     viewMode
+	filteredFeed    *rss.Feed // nil == show all feeds
+	searchInputTrap bool
+	searchQuery     string
+	prompt          promptKind
+	promptInput     string
+	promptFeed      string // URL of the feed a prompt acts on
+	width           int
+	height          int
+	loading         bool
+	statusMessage   string
+	articleScroll   int            // scroll position in article view
+	scrollPositions map[string]int // last scroll position by reader cache key
+	percentDigits   string         // number typed before % to jump through the article
+	articleCache    *content.Cache
+	imageMode       content.ImageMode
+	loadingArticle  bool
+	previewSeq      int                  // bumped on every selection the split layout might preview
+	selectedLink    int                  // footnote number of the selected link in the reader, 0 == none
+	linkDigits      string               // footnote number typed so far
+	linkHistory     []content.Link       // links opened in the reader, esc pops back
+	podcastMode     bool                 // only list articles with audio/video attachments
+	downloads       map[string]*download // in flight episode downloads by article ID
+	keys            KeyMap
+	showHelp        bool // help overlay listing the keys of the current view
+	helpScroll      int
+	detailsURL      string          // feed shown in the details panel, "" when closed
+	newArticles     map[string]bool // IDs of articles that arrived with a refresh, until they're read
+	lastClick       click
 }
 
+// number of rendered articles kept in memory
+const articleCacheSize = 32
+
 // combines an article with its source feed info
 type articleWithSource struct {
 	article   *rss.Article
@@ -50,13 +72,26 @@ type feedsLoadedMsg struct {
 	feeds []*rss.Feed
 }
 
+type prefetchDoneMsg struct {
+	fetched int
+	failed  int
+}
+
 type articleContentLoadedMsg struct {
-	url     string
-	content string
-	err     error
+	key string
+	err error
 }
 
-func NewModel(cfg *config.Config, state *config.State) Model {
+type articleImagesLoadedMsg struct {
+	key string
+}
+
+func NewModel(cfg *config.Config, state *config.State, keys KeyMap) Model {
+	// the setting is validated when it's loaded
+	imageMode, _ := content.ParseImageMode(cfg.Settings.Images, !config.NoColor())
+	articleCache := content.NewCache(articleCacheSize)
+	articleCache.SetImageMode(imageMode)
+
 	return Model{
 		config:          cfg,
 		state:           state,
@@ -70,6 +105,12 @@ func NewModel(cfg *config.Config, state *config.State) Model {
 		searchQuery:     "",
 		loading:         true,
 		statusMessage:   "Loading feeds...",
+		articleCache:    articleCache,
+		imageMode:       imageMode,
+		downloads:       make(map[string]*download),
+		scrollPositions: make(map[string]int),
+		newArticles:     make(map[string]bool),
+		keys:            keys,
 	}
 }
 
@@ -85,16 +126,68 @@ func loadFeeds(urls []string) tea.Cmd {
 	}
 }
 
-// creates a command to fetch article content
-func loadArticleContent(url string) tea.Cmd {
+// creates a command to load article content into the cache.
+// full content from the feed is used when present, otherwise the page is scraped
+func loadArticleContent(cache *content.Cache, article *rss.Article) tea.Cmd {
+	key := articleCacheKey(article)
 	return func() tea.Msg {
-		articleContent, err := content.GetArticleContent(url)
+		var err error
+		if article.Content != "" {
+			cache.Add(key, article.Link, article.Content)
+		} else {
+			err = cache.Load(article.Link)
+		}
 		return articleContentLoadedMsg{
-			url:     url,
-			content: articleContent,
-			err:     err,
+			key: key,
+			err: err,
+		}
+	}
+}
+
+// creates a command to download the images of cached article content. the
+// article is shown with alt text meanwhile and drawn again once they're in
+func loadArticleImages(cache *content.Cache, key string) tea.Cmd {
+	return func() tea.Msg {
+		if !cache.FetchImages(key) {
+			return nil
 		}
+		return articleImagesLoadedMsg{key: key}
+	}
+}
+
+// returns the key an article's content is cached under. content from the
+// feed is keyed by the article and a hash of the content, so it's never
+// mistaken for the scraped page and an updated entry is rendered afresh
+func articleCacheKey(article *rss.Article) string {
+	if article.Content != "" {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(article.Content))
+		return fmt.Sprintf("feed:%s#%x", article.GetArticleID(), h.Sum64())
+	}
+	if article.Link != "" {
+		return article.Link
 	}
+	return article.GetArticleID()
+}
+
+// creates a command to extract unread articles into the cache in the background
+func prefetchArticles(cache *content.Cache, urls []string, settings config.Settings) tea.Cmd {
+	return func() tea.Msg {
+		fetched, failed := cache.Prefetch(urls, settings.PrefetchConcurrency, settings.PrefetchPerHost)
+		return prefetchDoneMsg{fetched: fetched, failed: failed}
+	}
+}
+
+// returns the links of all unread articles, newest first
+func (m Model) unreadArticleLinks() []string {
+	var links []string
+	for _, item := range m.allArticles {
+		// articles with full content in the feed don't need scraping
+		if item.article.Link != "" && item.article.Content == "" && !m.IsArticleRead(item.article) {
+			links = append(links, item.article.Link)
+		}
+	}
+	return links
 }
 
 // creates a sorted list of all articles from all feeds
@@ -102,9 +195,7 @@ func (m *Model) buildArticles() {
 	m.allArticles = []articleWithSource{}
 
 	for _, feed := range m.feeds {
-		if feed.Error != nil {
-			continue
-		}
+		// feeds that failed to refresh still list the articles they had
 		for i := range feed.Articles {
 			m.allArticles = append(m.allArticles, articleWithSource{
 				article:   &feed.Articles[i],
@@ -119,12 +210,36 @@ func (m *Model) buildArticles() {
 	})
 }
 
-// returns articles filtered by search query if active
+// returns articles filtered by podcast mode and search query if active
 func (m Model) GetVisibleArticles() []articleWithSource {
+	articles := m.allArticles
+	if m.podcastMode {
+		articles = filterEpisodes(articles)
+	}
 	if m.searchQuery != "" {
-		return FilterArticles(m.allArticles, m.searchQuery)
+		return FilterArticles(articles, m.searchQuery)
+	}
+	return articles
+}
+
+// returns the ID of the selected article, "" when there's none
+func (m Model) currentArticleID() string {
+	if article := m.GetCurrentArticle(); article != nil {
+		return article.GetArticleID()
+	}
+	return ""
+}
+
+// moves the selection to the article with the given ID, or keeps it within
+// the list when the article is gone
+func (m *Model) selectArticle(id string) {
+	for i, item := range m.GetVisibleArticles() {
+		if item.article.GetArticleID() == id {
+			m.selectedArticle = i
+			return
+		}
 	}
-	return m.allArticles
+	m.moveArticleSelection(0)
 }
 
 func (m Model) GetCurrentArticle() *rss.Article {
@@ -143,6 +258,33 @@ func (m Model) GetCurrentSource() *rss.Feed {
 	return m.feeds[m.selectedSource]
 }
 
+// returns the saved search selected in sources view, if any.
+// saved searches are listed after the feeds
+func (m Model) GetCurrentSavedSearch() *config.SavedSearch {
+	idx := m.selectedSource - len(m.feeds)
+	if idx < 0 || idx >= len(m.config.Searches) {
+		return nil
+	}
+	return &m.config.Searches[idx]
+}
+
+// returns the number of selectable entries in sources view
+func (m Model) sourceCount() int {
+	return len(m.feeds) + len(m.config.Searches)
+}
+
+// restricts the articles list to a single feed
+func (m *Model) filterByFeed(feed *rss.Feed) {
+	m.filteredFeed = feed
+	m.allArticles = []articleWithSource{}
+	for i := range feed.Articles {
+		m.allArticles = append(m.allArticles, articleWithSource{
+			article:   &feed.Articles[i],
+			feedTitle: feed.Title,
+		})
+	}
+}
+
 func (m Model) IsArticleRead(article *rss.Article) bool {
 	if article == nil {
 		return false
@@ -158,6 +300,7 @@ func (m *Model) MarkCurrentArticleAsRead() {
 	}
 
 	m.state.MarkAsRead(article.GetArticleID())
+	delete(m.newArticles, article.GetArticleID())
 	_ = m.state.Save()
 }
 
@@ -198,7 +341,6 @@ func (m Model) GetUnreadCount(feed *rss.Feed) int {
 func (m *Model) RefreshFeeds() tea.Cmd {
 	m.loading = true
 	m.statusMessage = "Refreshing feeds..."
-	m.selectedArticle = 0
 	return loadFeeds(m.config.Feeds)
 }
 
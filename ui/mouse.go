@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// a second click on the same item within this long opens it
+const doubleClickTime = 400 * time.Millisecond
+
+// lines the reader scrolls per turn of the mouse wheel
+const wheelLines = 3
+
+// the last click, to tell double clicks
+type click struct {
+	at   time.Time
+	view viewMode
+	item int
+}
+
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.loading || m.prompt != noPrompt || m.searchInputTrap || m.detailsURL != "" {
+		return m, nil
+	}
+
+	delta := 0
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		delta = -1
+	case tea.MouseButtonWheelDown:
+		delta = 1
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress || m.showHelp {
+			return m, nil
+		}
+		view, _, y := m.paneAt(msg.X, msg.Y)
+		return m.click(view, y, time.Now())
+	default:
+		return m, nil
+	}
+
+	if m.showHelp {
+		maxScroll := max(len(m.helpLines())-m.helpHeight(), 0)
+		m.helpScroll = max(min(m.helpScroll+delta, maxScroll), 0)
+		return m, nil
+	}
+
+	// the wheel moves whatever is under the pointer, focused or not
+	view, _, _ := m.paneAt(msg.X, msg.Y)
+	switch view {
+	case articlesView:
+		m.moveArticleSelection(delta)
+	case sourcesView:
+		m.moveSourceSelection(delta)
+	case articleView:
+		m.scrollArticle(delta * wheelLines)
+	}
+	return m, nil
+}
+
+// selects the article or source on line y of a view, opening it on a
+// double click. clicking a pane of the split layout focuses it
+func (m Model) click(view viewMode, y int, at time.Time) (tea.Model, tea.Cmd) {
+	if view == articleView {
+		if m.currentView != articleView {
+			return m, m.openArticle()
+		}
+		return m, nil
+	}
+
+	list := m
+	if m.splitLayout() {
+		sources, articles, _ := m.paneWidths()
+		width := articles
+		if view == sourcesView {
+			width = sources
+		}
+		list = m.paneModel(width)
+	}
+
+	var owners []int
+	if view == articlesView {
+		_, owners = list.articleListLines()
+	} else {
+		_, owners = list.sourceListLines()
+	}
+
+	if m.currentView == articleView {
+		m.saveArticleScroll()
+		m.linkHistory = nil
+		m.clearLinkSelection()
+	}
+	m.currentView = view
+	if y < 0 || y >= len(owners) || owners[y] < 0 {
+		return m, nil
+	}
+
+	item := owners[y]
+	double := m.lastClick.view == view && m.lastClick.item == item && at.Sub(m.lastClick.at) < doubleClickTime
+	m.lastClick = click{at: at, view: view, item: item}
+
+	if view == articlesView {
+		m.selectedArticle = item
+		if double {
+			return m, m.openArticle()
+		}
+		return m, nil
+	}
+
+	m.selectedSource = item
+	if double {
+		return m, m.OpenCurrentSource()
+	}
+	return m, nil
+}
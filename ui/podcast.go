@@ -0,0 +1,228 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ohnurr/podcast"
+	"ohnurr/rss"
+)
+
+// an episode download in flight
+type download struct {
+	title   string
+	written int64
+	total   int64 // -1 if unknown
+}
+
+type downloadProgressMsg struct {
+	id      string
+	written int64
+	total   int64
+	ch      <-chan tea.Msg
+}
+
+type downloadDoneMsg struct {
+	id   string
+	path string
+	err  error
+}
+
+type playerExitedMsg struct {
+	id  string
+	err error
+}
+
+// returns only articles with an audio or video attachment
+func filterEpisodes(articles []articleWithSource) []articleWithSource {
+	episodes := make([]articleWithSource, 0)
+	for _, item := range articles {
+		if item.article.MediaEnclosure() != nil {
+			episodes = append(episodes, item)
+		}
+	}
+	return episodes
+}
+
+// plays the selected episode in the configured player, preferring a downloaded copy
+func (m *Model) PlayCurrentEpisode() tea.Cmd {
+	article := m.GetCurrentArticle()
+	if article == nil {
+		return nil
+	}
+
+	enclosure := article.MediaEnclosure()
+	if enclosure == nil {
+		return m.SetStatusMessage("No episode to play")
+	}
+
+	id := article.GetArticleID()
+	target := enclosure.URL
+	if path := m.state.DownloadPath(id); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			target = path
+		}
+	}
+
+	cmd, err := podcast.PlayerCommand(m.config.Settings.Player, target)
+	if err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Could not start player: %v", err))
+	}
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return playerExitedMsg{id: id, err: err}
+	})
+}
+
+// starts downloading the selected episode in the background
+func (m *Model) DownloadCurrentEpisode() tea.Cmd {
+	article := m.GetCurrentArticle()
+	if article == nil {
+		return nil
+	}
+
+	enclosure := article.MediaEnclosure()
+	if enclosure == nil {
+		return m.SetStatusMessage("No episode to download")
+	}
+
+	id := article.GetArticleID()
+	if _, ok := m.downloads[id]; ok {
+		return m.SetStatusMessage("Already downloading")
+	}
+	if path := m.state.DownloadPath(id); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return m.SetStatusMessage("Already downloaded: " + path)
+		}
+	}
+
+	dest := podcast.EpisodePath(m.config.Settings.DownloadDir, article.FeedTitle, article.Title, enclosure.URL, enclosure.Type)
+	m.downloads[id] = &download{title: article.Title, total: -1}
+	return startDownload(id, enclosure.URL, dest)
+}
+
+// runs a download in a goroutine, streaming progress back as messages
+func startDownload(id, url, dest string) tea.Cmd {
+	ch := make(chan tea.Msg, 1)
+
+	go func() {
+		err := podcast.Download(url, dest, func(written, total int64) {
+			// drop updates the UI hasn't caught up with yet
+			select {
+			case ch <- downloadProgressMsg{id: id, written: written, total: total, ch: ch}:
+			default:
+			}
+		})
+		ch <- downloadDoneMsg{id: id, path: dest, err: err}
+		close(ch)
+	}()
+
+	return waitForDownload(ch)
+}
+
+func waitForDownload(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+func (m Model) handlePodcastMsg(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case downloadProgressMsg:
+		if d, ok := m.downloads[msg.id]; ok {
+			d.written = msg.written
+			d.total = msg.total
+		}
+		return m, waitForDownload(msg.ch)
+
+	case downloadDoneMsg:
+		title := msg.id
+		if d, ok := m.downloads[msg.id]; ok {
+			title = d.title
+		}
+		delete(m.downloads, msg.id)
+
+		if msg.err != nil {
+			return m, m.SetStatusMessage(fmt.Sprintf("Download failed: %v", msg.err))
+		}
+		m.state.SetDownloaded(msg.id, msg.path)
+		_ = m.state.Save()
+		return m, m.SetStatusMessage("Downloaded: " + title)
+
+	case playerExitedMsg:
+		if msg.err != nil {
+			return m, m.SetStatusMessage(fmt.Sprintf("Player exited: %v", msg.err))
+		}
+		m.state.MarkAsPlayed(msg.id)
+		m.state.MarkAsRead(msg.id)
+		delete(m.newArticles, msg.id)
+		_ = m.state.Save()
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// returns duration, size and played/downloaded markers for an episode
+func (m Model) episodeInfo(article *rss.Article) string {
+	enclosure := article.MediaEnclosure()
+	if enclosure == nil {
+		return ""
+	}
+
+	var parts []string
+	if article.Duration > 0 {
+		parts = append(parts, formatDuration(article.Duration))
+	}
+	if enclosure.Length > 0 {
+		parts = append(parts, formatBytes(enclosure.Length))
+	}
+
+	id := article.GetArticleID()
+	if d, ok := m.downloads[id]; ok {
+		parts = append(parts, "⬇ "+d.percent())
+	} else if m.state.DownloadPath(id) != "" {
+		parts = append(parts, "⬇ downloaded")
+	}
+	if m.state.IsPlayed(id) {
+		parts = append(parts, "✓ played")
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+// summarises active downloads for the status bar
+func (m Model) downloadsStatus() string {
+	// sorted so the order doesn't jump around between renders
+	ids := slices.Sorted(maps.Keys(m.downloads))
+
+	var parts []string
+	for _, id := range ids {
+		d := m.downloads[id]
+		parts = append(parts, fmt.Sprintf("⬇ %s %s", truncate(d.title, 30), d.percent()))
+	}
+	return strings.Join(parts, " | ")
+}
+
+func (d *download) percent() string {
+	if d.total <= 0 {
+		return formatBytes(d.written)
+	}
+	return fmt.Sprintf("%d%%", d.written*100/d.total)
+}
+
+// formats a duration as H:MM:SS or M:SS
+func formatDuration(d time.Duration) string {
+	total := int(d.Seconds())
+	h, m, s := total/3600, (total%3600)/60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%d:%02d", m, s)
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type promptKind int
+
+const (
+	noPrompt promptKind = iota
+	saveSearchPrompt
+	addFeedPrompt
+	removeFeedPrompt
+	renameFeedPrompt
+	moveFeedPrompt
+)
+
+// reports whether the prompt asks a yes/no question rather than for text
+func (p promptKind) confirm() bool {
+	return p == removeFeedPrompt
+}
+
+// returns the label shown in front of the prompt input
+func (m Model) promptLabel() string {
+	switch m.prompt {
+	case saveSearchPrompt:
+		return "Save search as: "
+	case addFeedPrompt:
+		return "Add feed (site or feed URL): "
+	case removeFeedPrompt:
+		title := m.promptFeed
+		if i := m.feedIndex(m.promptFeed); i >= 0 {
+			title = m.feeds[i].Title
+		}
+		return "Remove " + title + "? (y/n)"
+	case renameFeedPrompt:
+		return "Rename feed to (empty for its own title): "
+	case moveFeedPrompt:
+		return "Move to folder (empty for none): "
+	}
+	return ""
+}
+
+// opens a single line text prompt in the status bar
+func (m *Model) openPrompt(kind promptKind, initial string) {
+	m.prompt = kind
+	m.promptInput = initial
+}
+
+// opens a prompt acting on a feed, the one selected in sources view
+func (m *Model) openFeedPrompt(kind promptKind, url, initial string) {
+	m.promptFeed = url
+	m.openPrompt(kind, initial)
+}
+
+func (m *Model) closePrompt() {
+	m.prompt = noPrompt
+	m.promptInput = ""
+}
+
+// handlePromptInput processes keyboard input while a prompt is open
+func (m Model) handlePromptInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.prompt.confirm() {
+		// anything but yes cancels
+		kind := m.prompt
+		m.closePrompt()
+		if strings.EqualFold(msg.String(), "y") {
+			return m.submitPrompt(kind, "")
+		}
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEscape:
+		m.closePrompt()
+		return m, nil
+
+	case tea.KeyEnter:
+		kind, input := m.prompt, m.promptInput
+		m.closePrompt()
+		return m.submitPrompt(kind, input)
+
+	case tea.KeyBackspace:
+		if len(m.promptInput) > 0 {
+			runes := []rune(m.promptInput)
+			m.promptInput = string(runes[:len(runes)-1])
+		}
+		return m, nil
+
+	case tea.KeySpace:
+		m.promptInput += " "
+		return m, nil
+
+	case tea.KeyRunes:
+		m.promptInput += string(msg.Runes)
+		return m, nil
+	}
+
+	return m, nil
+}
+
+// runs the action behind a prompt once the user hits enter
+func (m Model) submitPrompt(kind promptKind, input string) (tea.Model, tea.Cmd) {
+	switch kind {
+	case saveSearchPrompt:
+		return m, m.SaveCurrentSearch(input)
+	case addFeedPrompt:
+		return m, m.AddFeed(input)
+	case removeFeedPrompt:
+		return m, m.RemoveFeed(m.promptFeed)
+	case renameFeedPrompt:
+		return m, m.RenameFeed(m.promptFeed, input)
+	case moveFeedPrompt:
+		return m, m.MoveFeed(m.promptFeed, input)
+	}
+	return m, nil
+}
+
+func (m Model) renderPrompt() string {
+	if m.prompt.confirm() {
+		return m.promptLabel()
+	}
+	return m.promptLabel() + m.promptInput + selectedStyle.Render("_")
+}
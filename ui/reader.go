@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// shows the selected article in the reader, marking it read and loading
+// its content if it isn't cached
+func (m *Model) openArticle() tea.Cmd {
+	article := m.GetCurrentArticle()
+	if article == nil {
+		return m.SetStatusMessage("Could not get article")
+	}
+
+	m.currentView = articleView
+	m.linkHistory = nil
+	m.clearLinkSelection()
+	m.restoreArticleScroll()
+
+	m.MarkCurrentArticleAsRead()
+	return m.loadCurrentArticle()
+}
+
+// starts loading the selected article's content unless it's cached
+func (m *Model) loadCurrentArticle() tea.Cmd {
+	article := m.GetCurrentArticle()
+	m.loadingArticle = !m.articleCache.Has(articleCacheKey(article))
+	if !m.loadingArticle {
+		return nil
+	}
+	return loadArticleContent(m.articleCache, article)
+}
+
+// opens the next (delta 1) or previous (delta -1) article in the list
+func (m *Model) StepArticle(delta int) tea.Cmd {
+	next := m.selectedArticle + delta
+	if next < 0 || next >= len(m.GetVisibleArticles()) {
+		if delta > 0 {
+			return m.SetStatusMessage("Last article")
+		}
+		return m.SetStatusMessage("First article")
+	}
+
+	m.saveArticleScroll()
+	m.selectedArticle = next
+	return m.openArticle()
+}
+
+// opens the next unread article after the current one in the list
+func (m *Model) NextUnreadArticle() tea.Cmd {
+	visibleArticles := m.GetVisibleArticles()
+	for i := m.selectedArticle + 1; i < len(visibleArticles); i++ {
+		if !m.IsArticleRead(visibleArticles[i].article) {
+			m.saveArticleScroll()
+			m.selectedArticle = i
+			return m.openArticle()
+		}
+	}
+	return m.SetStatusMessage("No more unread articles")
+}
+
+// returns the number of rendered lines in the reader and how many of them
+// fit on screen under the header
+func (m Model) articlePage() (lines, height int) {
+	rendered := m.readerContent()
+	if rendered == nil {
+		return 0, 0
+	}
+	lines = strings.Count(rendered.Text, "\n") + 1
+	_, screenHeight := m.readerSize()
+	height = screenHeight - len(m.articleHeader()) - 1 // status bar
+	return lines, max(height, 1)
+}
+
+// returns the scroll position that shows the end of the article
+func (m Model) maxArticleScroll() int {
+	lines, height := m.articlePage()
+	return max(lines-height, 0)
+}
+
+// scrolls the reader to line, staying within the article
+func (m *Model) setArticleScroll(line int) {
+	m.articleScroll = max(min(line, m.maxArticleScroll()), 0)
+}
+
+// scrolls the reader by delta lines
+func (m *Model) scrollArticle(delta int) {
+	m.setArticleScroll(min(m.articleScroll, m.maxArticleScroll()) + delta)
+}
+
+// scrolls the reader by a fraction of the screen, e.g. 0.5 for half a page down
+func (m *Model) scrollArticlePages(pages float64) {
+	_, height := m.articlePage()
+	m.scrollArticle(int(float64(height) * pages))
+}
+
+// scrolls to percent of the way through the article, as typed before %
+func (m *Model) jumpToPercent() {
+	percent, err := strconv.Atoi(m.percentDigits)
+	m.percentDigits = ""
+	if err != nil {
+		return
+	}
+	m.clearLinkSelection()
+	m.setArticleScroll(min(percent, 100) * m.maxArticleScroll() / 100)
+}
+
+// returns how far through the article the reader is, e.g. "42%"
+func (m Model) scrollPercent() string {
+	if m.loadingArticle || m.readerContent() == nil {
+		return ""
+	}
+	maxScroll := m.maxArticleScroll()
+	if maxScroll == 0 {
+		return "100%"
+	}
+	return fmt.Sprintf("%d%%", min(m.articleScroll, maxScroll)*100/maxScroll)
+}
+
+// remembers where the reader is in what it's showing, so coming back to
+// it picks up from there
+func (m *Model) saveArticleScroll() {
+	if m.currentView != articleView {
+		return
+	}
+	if key, _ := m.readerTarget(); key != "" {
+		m.scrollPositions[key] = min(m.articleScroll, m.maxArticleScroll())
+	}
+}
+
+// restores the remembered position of what the reader is showing
+func (m *Model) restoreArticleScroll() {
+	key, _ := m.readerTarget()
+	m.articleScroll = m.scrollPositions[key]
+}
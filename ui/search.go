@@ -1,8 +1,12 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ohnurr/config"
 	"ohnurr/rss"
 )
 
@@ -12,11 +16,11 @@ func FilterArticles(articles []articleWithSource, query string) []articleWithSou
 		return articles
 	}
 
-	query = strings.ToLower(query)
+	q := parseSearchQuery(query)
 	filtered := make([]articleWithSource, 0)
 
 	for _, item := range articles {
-		if matchesSearch(item, query) {
+		if q.matches(item) {
 			filtered = append(filtered, item)
 		}
 	}
@@ -24,6 +28,84 @@ func FilterArticles(articles []articleWithSource, query string) []articleWithSou
 	return filtered
 }
 
+// a search query split into free text and field filters like "author:jane"
+type searchQuery struct {
+	text    string
+	filters []searchFilter
+}
+
+type searchFilter struct {
+	field string
+	value string
+}
+
+// fields that can be filtered on with "field:value"
+var searchFields = map[string]string{
+	"author":    "author",
+	"tag":       "tag",
+	"category":  "tag",
+	"feed":      "feed",
+	"enclosure": "enclosure",
+}
+
+func parseSearchQuery(query string) searchQuery {
+	var q searchQuery
+	var text []string
+
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		name, value, ok := strings.Cut(term, ":")
+		field, known := searchFields[name]
+		if ok && known && value != "" {
+			q.filters = append(q.filters, searchFilter{field: field, value: value})
+			continue
+		}
+		text = append(text, term)
+	}
+
+	q.text = strings.Join(text, " ")
+	return q
+}
+
+// checks if an article matches the free text and every field filter
+func (q searchQuery) matches(item articleWithSource) bool {
+	for _, f := range q.filters {
+		if !f.matches(item) {
+			return false
+		}
+	}
+	return q.text == "" || matchesSearch(item, q.text)
+}
+
+func (f searchFilter) matches(item articleWithSource) bool {
+	article := item.article
+
+	switch f.field {
+	case "author":
+		return containsAny(article.Authors, f.value)
+	case "tag":
+		return containsAny(article.Categories, f.value)
+	case "feed":
+		return strings.Contains(strings.ToLower(item.feedTitle), f.value)
+	case "enclosure":
+		for _, e := range article.Enclosures {
+			if strings.Contains(strings.ToLower(e.Type), f.value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checks if any of the values contains the (lower case) substring
+func containsAny(values []string, substr string) bool {
+	for _, v := range values {
+		if strings.Contains(strings.ToLower(v), substr) {
+			return true
+		}
+	}
+	return false
+}
+
 // checks if an article matches the search query
 func FilterArticlesByContent(article *rss.Article, query string) bool {
 	if query == "" {
@@ -64,5 +146,78 @@ func matchesSearch(item articleWithSource, query string) bool {
 		return true
 	}
 
+	// authors
+	if containsAny(article.Authors, query) {
+		return true
+	}
+
 	return false
 }
+
+// returns the loaded feed with the given URL
+func (m Model) findFeed(url string) *rss.Feed {
+	for _, feed := range m.feeds {
+		if feed.URL == url {
+			return feed
+		}
+	}
+	return nil
+}
+
+// returns the number of unread articles matching a saved search
+func (m Model) GetSavedSearchUnreadCount(search config.SavedSearch) int {
+	q := parseSearchQuery(search.Query)
+	count := 0
+	for _, feed := range m.feeds {
+		if search.Feed != "" && feed.URL != search.Feed {
+			continue
+		}
+		for i := range feed.Articles {
+			article := &feed.Articles[i]
+			item := articleWithSource{article: article, feedTitle: feed.Title}
+			if q.matches(item) && !m.state.IsRead(article.GetArticleID()) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// saves the active search query and source filter under the given name
+func (m *Model) SaveCurrentSearch(name string) tea.Cmd {
+	search := config.SavedSearch{
+		Name:  name,
+		Query: m.searchQuery,
+	}
+	if m.filteredFeed != nil {
+		search.Feed = m.filteredFeed.URL
+	}
+
+	if err := m.config.AddSearch(search); err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Could not save search: %v", err))
+	}
+	if err := m.config.Save(); err != nil {
+		return m.SetStatusMessage(fmt.Sprintf("Error saving config: %v", err))
+	}
+	return m.SetStatusMessage("Saved search: " + strings.TrimSpace(name))
+}
+
+// shows the articles of a saved search as if it were a feed
+func (m *Model) ApplySavedSearch(search config.SavedSearch) tea.Cmd {
+	if search.Feed != "" {
+		feed := m.findFeed(search.Feed)
+		if feed == nil {
+			return m.SetStatusMessage("Feed for saved search not found: " + search.Feed)
+		}
+		m.filterByFeed(feed)
+	} else {
+		m.filteredFeed = nil
+		m.buildArticles()
+	}
+
+	m.searchQuery = search.Query
+	m.searchInputTrap = false
+	m.selectedArticle = 0
+	m.currentView = articlesView
+	return m.SetStatusMessage("Saved search: " + search.Name)
+}
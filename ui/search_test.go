@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"ohnurr/config"
+	"ohnurr/rss"
+)
+
+func TestFilterArticles(t *testing.T) {
+	articles := []articleWithSource{
+		{
+			article: &rss.Article{
+				Title:      "Release notes",
+				Authors:    []string{"Jane Doe"},
+				Categories: []string{"Go", "Releases"},
+			},
+			feedTitle: "Go Blog",
+		},
+		{
+			article: &rss.Article{
+				Title:      "Episode 12",
+				Authors:    []string{"John Smith"},
+				Categories: []string{"Audio"},
+				Enclosures: []rss.Enclosure{{URL: "https://example.com/12.mp3", Type: "audio/mpeg"}},
+			},
+			feedTitle: "Weekly Podcast",
+		},
+		{
+			article: &rss.Article{
+				Title:       "Screencast",
+				Description: "Writing a feed reader in Go",
+				Authors:     []string{"Jane Doe"},
+				Enclosures:  []rss.Enclosure{{URL: "https://example.com/cast.mp4", Type: "video/mp4"}},
+			},
+			feedTitle: "Weekly Podcast",
+		},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string // titles
+	}{
+		{name: "empty", query: "", want: []string{"Release notes", "Episode 12", "Screencast"}},
+		{name: "free text", query: "go", want: []string{"Release notes", "Screencast"}},
+		{name: "author", query: "author:jane", want: []string{"Release notes", "Screencast"}},
+		{name: "author ignores case", query: "Author:SMITH", want: []string{"Episode 12"}},
+		{name: "tag", query: "tag:releases", want: []string{"Release notes"}},
+		{name: "category is tag", query: "category:audio", want: []string{"Episode 12"}},
+		{name: "feed", query: "feed:podcast", want: []string{"Episode 12", "Screencast"}},
+		{name: "enclosure", query: "enclosure:video", want: []string{"Screencast"}},
+		{name: "filters combine", query: "feed:podcast author:jane", want: []string{"Screencast"}},
+		{name: "filter and text", query: "author:jane notes", want: []string{"Release notes"}},
+		{name: "unknown field is text", query: "title:release", want: nil},
+		{name: "empty value is text", query: "author:", want: nil},
+		{name: "no match", query: "tag:rust", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range FilterArticles(articles, tt.query) {
+				got = append(got, item.article.Title)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("FilterArticles(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModel_GetSavedSearchUnreadCount(t *testing.T) {
+	m := Model{
+		state: &config.State{ReadArticles: map[string]bool{"go-2": true}},
+		feeds: []*rss.Feed{
+			{
+				URL:   "https://example.com/go",
+				Title: "Go Blog",
+				Articles: []rss.Article{
+					{GUID: "go-1", Title: "Go 1.25", Categories: []string{"Go"}},
+					{GUID: "go-2", Title: "Go 1.24", Categories: []string{"Go"}},
+				},
+			},
+			{
+				// failed to refresh, but still lists its articles
+				URL:   "https://example.com/weekly",
+				Title: "Weekly",
+				Error: errors.New("timeout"),
+				Articles: []rss.Article{
+					{GUID: "weekly-1", Title: "Go generics", Categories: []string{"Go"}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		search config.SavedSearch
+		want   int
+	}{
+		{name: "all feeds", search: config.SavedSearch{Name: "go", Query: "tag:go"}, want: 2},
+		{name: "one feed", search: config.SavedSearch{Name: "go", Query: "tag:go", Feed: "https://example.com/go"}, want: 1},
+		{name: "failed feed", search: config.SavedSearch{Name: "weekly", Query: "generics", Feed: "https://example.com/weekly"}, want: 1},
+		{name: "no match", search: config.SavedSearch{Name: "rust", Query: "tag:rust"}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.GetSavedSearchUnreadCount(tt.search); got != tt.want {
+				t.Errorf("GetSavedSearchUnreadCount(%q) = %d, want %d", tt.search.Query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package ui
+
+import (
+	lg "github.com/charmbracelet/lipgloss"
+
+	"ohnurr/config"
+	"ohnurr/content"
+)
+
+// colours and styles of the interface, set from the theme
+var (
+	activeColor   lg.Color
+	inactiveColor lg.Color
+	unreadColor   lg.Color
+	titleColor    lg.Color
+	accentColor   lg.Color
+
+	articleTitleStyle     lg.Style
+	articleTitleReadStyle lg.Style
+	selectedStyle         lg.Style
+	dimStyle              lg.Style
+	descriptionStyle      lg.Style
+	sourceStyle           lg.Style
+	headerStyle           lg.Style
+	statusStyle           lg.Style
+	unreadDotStyle        lg.Style
+
+	paneStyle        lg.Style
+	focusedPaneStyle lg.Style
+	helpBoxStyle     lg.Style
+	helpKeyStyle     lg.Style
+)
+
+func init() {
+	setStyles(config.DefaultTheme())
+}
+
+// applies the theme named in settings to the interface and to articles.
+// "auto" picks the dark or light theme to suit the terminal's background
+func SetTheme(name string) error {
+	if name == "auto" {
+		name = "dark"
+		if !config.NoColor() && !lg.HasDarkBackground() {
+			name = "light"
+		}
+	}
+
+	theme, err := config.LoadTheme(name)
+	if err != nil {
+		return err
+	}
+	setStyles(theme)
+	content.SetTheme(articleTheme(theme))
+	return nil
+}
+
+// returns the colours of the theme that articles are rendered in
+func articleTheme(t config.Theme) content.Theme {
+	return content.Theme{
+		Heading:              t.Heading,
+		Subheading:           t.Subheading,
+		Code:                 t.Code,
+		CodeBackground:       t.CodeBackground,
+		InlineCode:           t.InlineCode,
+		InlineCodeBackground: t.InlineCodeBackground,
+		Footnote:             t.Footnote,
+		Dim:                  t.Dim,
+		Caption:              t.Caption,
+		Syntax:               t.Syntax,
+	}
+}
+
+func setStyles(t config.Theme) {
+	activeColor = lg.Color(t.Active)
+	inactiveColor = lg.Color(t.Inactive)
+	unreadColor = lg.Color(t.Unread)
+	titleColor = lg.Color(t.Title)
+	accentColor = lg.Color(t.Accent)
+
+	articleTitleStyle = lg.NewStyle().
+		Foreground(titleColor).
+		Bold(true)
+
+	articleTitleReadStyle = lg.NewStyle().
+		Foreground(inactiveColor)
+
+	selectedStyle = lg.NewStyle().
+		Foreground(activeColor).
+		Bold(true)
+
+	dimStyle = lg.NewStyle().
+		Foreground(inactiveColor)
+
+	descriptionStyle = lg.NewStyle().
+		Foreground(lg.Color(t.Text))
+
+	sourceStyle = lg.NewStyle().
+		Foreground(accentColor).
+		Italic(true)
+
+	headerStyle = lg.NewStyle().
+		Foreground(titleColor).
+		Bold(true).
+		Padding(0, 1)
+
+	statusStyle = lg.NewStyle().
+		Foreground(lg.Color(t.Status)).
+		Padding(0, 1)
+
+	unreadDotStyle = lg.NewStyle().
+		Foreground(unreadColor).
+		Bold(true)
+
+	paneStyle = lg.NewStyle().
+		Border(lg.RoundedBorder()).
+		BorderForeground(inactiveColor)
+
+	focusedPaneStyle = paneStyle.
+		BorderForeground(activeColor)
+
+	helpBoxStyle = lg.NewStyle().
+		Border(lg.RoundedBorder()).
+		BorderForeground(activeColor).
+		Padding(0, 2)
+
+	helpKeyStyle = lg.NewStyle().
+		Foreground(activeColor).
+		Bold(true)
+}
@@ -3,56 +3,143 @@ package ui
 import (
 	"fmt"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pkg/browser"
 )
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	before, _ := m.readerTarget()
+	updated, cmd := m.update(msg)
+
+	// in the split layout the reader previews the selected article
+	next, ok := updated.(Model)
+	if !ok || !next.splitLayout() || next.currentView == articleView {
+		return updated, cmd
+	}
+	if after, _ := next.readerTarget(); after != before || !m.splitLayout() {
+		cmd = tea.Batch(cmd, next.schedulePreview())
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.currentView == articleView {
+			// the article reflows, keep the scroll position within it
+			m.scrollArticle(0)
+		}
 		return m, nil
 
 	case feedsLoadedMsg:
+		selected := m.currentArticleID()
+		added := m.noteNewArticles(msg.feeds)
+		m.noteFetched(msg.feeds)
 		m.feeds = msg.feeds
-		m.buildArticles()
+		for _, feed := range m.feeds {
+			m.applyFeedMeta(feed)
+		}
+		m.sortFeeds()
+		m.rebuildArticles()
 		m.loading = false
 		m.statusMessage = ""
-		// reset selections if out of bounds
-		if m.selectedArticle >= len(m.allArticles) {
-			m.selectedArticle = 0
-		}
-		if m.selectedSource >= len(m.feeds) {
+		// keep the cursor on the same article, reset the source if out of bounds
+		m.selectArticle(selected)
+		if m.selectedSource >= m.sourceCount() {
 			m.selectedSource = 0
 		}
-		return m, nil
+
+		var cmds []tea.Cmd
+		switch {
+		case added == 1:
+			cmds = append(cmds, m.SetStatusMessage("1 new article"))
+		case added > 0:
+			cmds = append(cmds, m.SetStatusMessage(fmt.Sprintf("%d new articles", added)))
+		}
+		if m.config.Settings.Prefetch {
+			cmds = append(cmds, prefetchArticles(m.articleCache, m.unreadArticleLinks(), m.config.Settings))
+		}
+		return m, tea.Batch(cmds...)
+
+	case feedDiscoveredMsg:
+		return m, m.subscribe(msg)
+
+	case feedLoadedMsg:
+		return m, m.updateFeed(msg.feed)
+
+	case prefetchDoneMsg:
+		if msg.fetched == 0 && msg.failed == 0 {
+			return m, nil
+		}
+		status := fmt.Sprintf("Prefetched %d articles", msg.fetched)
+		if msg.failed > 0 {
+			status += fmt.Sprintf(" (%d failed)", msg.failed)
+		}
+		return m, m.SetStatusMessage(status)
 
 	case articleContentLoadedMsg:
+		// stepping through articles can leave loads behind that we no longer wait on
+		if key, _ := m.readerTarget(); key != msg.key {
+			return m, nil
+		}
 		m.loadingArticle = false
 		if msg.err != nil {
 			m.statusMessage = fmt.Sprintf("Error loading article: %v", msg.err)
-		} else {
-			m.cachedArticleURL = msg.url
-			m.cachedArticleContent = msg.content
+			return m, nil
+		}
+		return m, loadArticleImages(m.articleCache, msg.key)
+
+	case previewTickMsg:
+		// the selection has moved on, or the preview is no longer shown
+		if msg.seq != m.previewSeq || !m.splitLayout() || m.currentView == articleView {
+			return m, nil
 		}
+		return m, m.previewArticle()
+
+	case articleImagesLoadedMsg:
+		// nothing to do, the article is drawn again with its images
 		return m, nil
 
+	case downloadProgressMsg, downloadDoneMsg, playerExitedMsg:
+		return m.handlePodcastMsg(msg)
+
 	case clearStatusMsg:
 		m.statusMessage = ""
 		return m, nil
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.KeyMsg:
+		if m.prompt != noPrompt {
+			return m.handlePromptInput(msg)
+		}
+
+		if m.showHelp {
+			return m.handleHelpKeys(msg)
+		}
+
+		if m.detailsURL != "" {
+			return m.handleFeedDetailsKeys(msg)
+		}
+
 		if m.searchInputTrap {
 			return m.handleSearchInput(msg)
 		}
 
 		// global keybindings (when not in search mode)
-		switch msg.String() {
-		case "q", "ctrl+c":
+		switch {
+		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
 
-		case "s":
+		case key.Matches(msg, m.keys.Help):
+			m.openHelp()
+			return m, nil
+
+		case key.Matches(msg, m.keys.Sources):
 			// toggle sources and articles view
 			switch m.currentView {
 			case articlesView:
@@ -62,11 +149,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
-		case "r":
+		case key.Matches(msg, m.keys.Refresh):
 			// refresh
 			return m, m.RefreshFeeds()
 
-		case "/":
+		case key.Matches(msg, m.keys.RefreshFeed):
+			// refresh just the selected feed, or the selected article's
+			return m, m.RefreshFeed(m.selectedFeed())
+
+		case key.Matches(msg, m.keys.Search):
 			// enter search mode (only in articles view)
 			if m.currentView == articlesView {
 				m.searchInputTrap = true
@@ -75,7 +166,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
-		case "esc":
+		case key.Matches(msg, m.keys.FocusLeft) && m.splitLayout():
+			return m, m.moveFocus(-1)
+
+		case key.Matches(msg, m.keys.FocusRight) && m.splitLayout():
+			return m, m.moveFocus(1)
+
+		case key.Matches(msg, m.keys.ClearSearch):
 			// clear search if search query is active
 			if m.searchQuery != "" && m.currentView == articlesView {
 				m.searchQuery = ""
@@ -98,24 +195,42 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleArticlesViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	visibleArticles := m.GetVisibleArticles()
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		m.moveArticleSelection(-1)
 
-	switch msg.String() {
-	case "up", "k":
-		if m.selectedArticle > 0 {
-			m.selectedArticle--
-		}
-
-	case "down", "j":
-		if len(visibleArticles) > 0 && m.selectedArticle < len(visibleArticles)-1 {
-			m.selectedArticle++
-		}
+	case key.Matches(msg, m.keys.Down):
+		m.moveArticleSelection(1)
 
-	case "m":
+	case key.Matches(msg, m.keys.ToggleRead):
 		// manually update read status
 		m.ToggleCurrentArticleReadStatus()
 
-	case "o":
+	case key.Matches(msg, m.keys.Podcasts):
+		// toggle podcast mode
+		m.podcastMode = !m.podcastMode
+		m.selectedArticle = 0
+		if m.podcastMode {
+			return m, m.SetStatusMessage("Showing podcast episodes")
+		}
+		return m, m.SetStatusMessage("Showing all articles")
+
+	case key.Matches(msg, m.keys.Play):
+		// play episode in external player
+		return m, m.PlayCurrentEpisode()
+
+	case key.Matches(msg, m.keys.Download):
+		// download episode
+		return m, m.DownloadCurrentEpisode()
+
+	case key.Matches(msg, m.keys.SaveSearch):
+		// save the active search as a virtual feed
+		if m.searchQuery == "" {
+			return m, m.SetStatusMessage("Nothing to save, search first")
+		}
+		m.openPrompt(saveSearchPrompt, "")
+
+	case key.Matches(msg, m.keys.OpenBrowser):
 		// open article in browser
 		article := m.GetCurrentArticle()
 		if article != nil && article.Link != "" {
@@ -127,75 +242,120 @@ func (m Model) handleArticlesViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m, m.SetStatusMessage("Opened in browser")
 		}
 
-	case "enter":
+	case key.Matches(msg, m.keys.Open):
 		// view article
-		article := m.GetCurrentArticle()
-		if article == nil {
-			return m, m.SetStatusMessage("Could not get article")
-		}
-
-		m.currentView = articleView
-		m.articleScroll = 0 // reset scroll when entering article
-
-		m.MarkCurrentArticleAsRead()
-
-		// check if article is cached
-		if m.cachedArticleURL != article.Link {
-			m.loadingArticle = true
-			m.cachedArticleURL = ""
-			m.cachedArticleContent = ""
-			return m, loadArticleContent(article.Link)
-		}
+		return m, m.openArticle()
 	}
 
 	return m, nil
 }
 
 func (m Model) handleArticleViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "esc":
+	pressed := msg.String()
+	digit := len(pressed) == 1 && pressed[0] >= '0' && pressed[0] <= '9'
+	if !digit {
+		m.linkDigits = ""
+		if !key.Matches(msg, m.keys.JumpPercent) {
+			m.percentDigits = ""
+		}
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		// clear link selection, then go back through opened links, then to the list
+		if m.selectedLink > 0 {
+			m.clearLinkSelection()
+			return m, nil
+		}
+		m.saveArticleScroll()
+		if n := len(m.linkHistory); n > 0 {
+			m.linkHistory = m.linkHistory[:n-1]
+			m.restoreArticleScroll()
+			return m, nil
+		}
 		// return to articles list
 		m.currentView = articlesView
 		return m, nil
 
-	case "o":
-		// open article in browser
-		article := m.GetCurrentArticle()
-		if article != nil && article.Link != "" {
-			err := browser.OpenURL(article.Link)
+	case key.Matches(msg, m.keys.OpenBrowser):
+		// open article (or the link being read) in browser
+		url, _ := m.readerTarget()
+		if len(m.linkHistory) == 0 {
+			if article := m.GetCurrentArticle(); article != nil {
+				url = article.Link
+			}
+		}
+		if url != "" {
+			err := browser.OpenURL(url)
 			if err != nil {
 				return m, m.SetStatusMessage("Failed to open browser")
 			}
 			return m, m.SetStatusMessage("Opened in browser")
 		}
 
-	case "up", "k":
-		if m.articleScroll > 0 {
-			m.articleScroll--
-		}
+	case key.Matches(msg, m.keys.NextLink):
+		m.cycleLink(1)
 
-	case "down", "j":
-		m.articleScroll++
+	case key.Matches(msg, m.keys.PrevLink):
+		m.cycleLink(-1)
 
-	case "pgup":
-		// scroll up by page
-		pageSize := m.height - 4 // account for header and status bar
-		m.articleScroll -= pageSize
-		if m.articleScroll < 0 {
-			m.articleScroll = 0
-		}
+	case key.Matches(msg, m.keys.JumpPercent):
+		m.jumpToPercent()
+
+	case key.Matches(msg, m.keys.OpenLink):
+		return m, m.OpenCurrentLink()
 
-	case "pgdown":
-		// scroll down by page
-		pageSize := m.height - 4
-		m.articleScroll += pageSize
+	case key.Matches(msg, m.keys.ReadLink):
+		return m, m.ReadCurrentLink()
 
-	case "g":
-		m.articleScroll = 0
+	case key.Matches(msg, m.keys.Play):
+		return m, m.PlayCurrentEpisode()
 
-	case "G":
-		// scroll to bottom
-		m.articleScroll = m.height
+	case key.Matches(msg, m.keys.Download):
+		return m, m.DownloadCurrentEpisode()
+
+	case key.Matches(msg, m.keys.Export):
+		return m, m.ExportCurrentArticle()
+
+	case key.Matches(msg, m.keys.NextArticle):
+		return m, m.StepArticle(1)
+
+	case key.Matches(msg, m.keys.PrevArticle):
+		return m, m.StepArticle(-1)
+
+	case key.Matches(msg, m.keys.NextUnread):
+		return m, m.NextUnreadArticle()
+
+	case key.Matches(msg, m.keys.Up):
+		m.scrollArticle(-1)
+
+	case key.Matches(msg, m.keys.Down):
+		m.scrollArticle(1)
+
+	case key.Matches(msg, m.keys.PageUp):
+		m.scrollArticlePages(-1)
+
+	case key.Matches(msg, m.keys.PageDown):
+		m.scrollArticlePages(1)
+
+	case key.Matches(msg, m.keys.HalfPageUp):
+		m.scrollArticlePages(-0.5)
+
+	case key.Matches(msg, m.keys.HalfPageDown):
+		m.scrollArticlePages(0.5)
+
+	case key.Matches(msg, m.keys.Top):
+		m.setArticleScroll(0)
+
+	case key.Matches(msg, m.keys.Bottom):
+		m.setArticleScroll(m.maxArticleScroll())
+
+	case digit:
+		// footnote numbers, or how far to jump through the article
+		m.selectLinkDigit(pressed)
+		if len(m.percentDigits) < 3 {
+			m.percentDigits += pressed
+		}
 	}
 
 	return m, nil
@@ -237,39 +397,64 @@ func (m Model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleSourcesViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
-		if m.selectedSource > 0 {
-			m.selectedSource--
+	switch {
+	case key.Matches(msg, m.keys.Up):
+		m.moveSourceSelection(-1)
+
+	case key.Matches(msg, m.keys.Down):
+		m.moveSourceSelection(1)
+
+	case key.Matches(msg, m.keys.Open):
+		return m, m.OpenCurrentSource()
+
+	case key.Matches(msg, m.keys.AddFeed):
+		m.openPrompt(addFeedPrompt, "")
+
+	case key.Matches(msg, m.keys.FeedDetails):
+		if feed := m.GetCurrentSource(); feed != nil {
+			m.detailsURL = feed.URL
 		}
 
-	case "down", "j":
-		if len(m.feeds) > 0 && m.selectedSource < len(m.feeds)-1 {
-			m.selectedSource++
+	case key.Matches(msg, m.keys.OpenBrowser):
+		return m, m.OpenFeedSite(m.GetCurrentSource())
+
+	case key.Matches(msg, m.keys.RenameFeed):
+		if feed := m.GetCurrentSource(); feed != nil {
+			m.openFeedPrompt(renameFeedPrompt, feed.URL, m.config.FeedMeta[feed.URL].Title)
 		}
 
-	case "enter":
-		// filter by selected source
-		selectedFeed := m.GetCurrentSource()
-		if selectedFeed != nil {
-			m.filteredFeed = selectedFeed
-			m.currentView = articlesView
-			m.selectedArticle = 0
-			// rebuild articles list
-			m.allArticles = []articleWithSource{}
-			for i := range selectedFeed.Articles {
-				m.allArticles = append(m.allArticles, articleWithSource{
-					article:   &selectedFeed.Articles[i],
-					feedTitle: selectedFeed.Title,
-				})
-			}
-			return m, m.SetStatusMessage("Filtered by: " + selectedFeed.Title)
+	case key.Matches(msg, m.keys.MoveFeed):
+		if feed := m.GetCurrentSource(); feed != nil {
+			m.openFeedPrompt(moveFeedPrompt, feed.URL, m.config.FeedMeta[feed.URL].Folder)
+		}
+
+	case key.Matches(msg, m.keys.Delete):
+		// remove selected feed once confirmed, saved searches go straight away
+		if feed := m.GetCurrentSource(); feed != nil {
+			m.openFeedPrompt(removeFeedPrompt, feed.URL, "")
+			return m, nil
+		}
+		search := m.GetCurrentSavedSearch()
+		if search == nil {
+			return m, nil
+		}
+		name := search.Name
+		if err := m.config.RemoveSearch(name); err != nil {
+			return m, m.SetStatusMessage(fmt.Sprintf("Error removing search: %v", err))
 		}
+		if err := m.config.Save(); err != nil {
+			return m, m.SetStatusMessage(fmt.Sprintf("Error saving config: %v", err))
+		}
+		if m.selectedSource >= m.sourceCount() {
+			m.selectedSource = max(m.sourceCount()-1, 0)
+		}
+		return m, m.SetStatusMessage("Removed saved search: " + name)
 
-	case "a":
+	case key.Matches(msg, m.keys.ShowAll):
 		// show all feeds
-		if m.filteredFeed != nil {
+		if m.filteredFeed != nil || m.searchQuery != "" {
 			m.filteredFeed = nil
+			m.searchQuery = ""
 			m.buildArticles()
 			m.selectedArticle = 0
 			m.currentView = articlesView
@@ -279,3 +464,32 @@ func (m Model) handleSourcesViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 	return m, nil
 }
+
+// moves the article selection by delta, staying within the list
+func (m *Model) moveArticleSelection(delta int) {
+	last := len(m.GetVisibleArticles()) - 1
+	m.selectedArticle = max(min(m.selectedArticle+delta, last), 0)
+}
+
+// moves the source selection by delta, staying within the list
+func (m *Model) moveSourceSelection(delta int) {
+	m.selectedSource = max(min(m.selectedSource+delta, m.sourceCount()-1), 0)
+}
+
+// shows the articles of the selected feed or saved search
+func (m *Model) OpenCurrentSource() tea.Cmd {
+	// filter by selected source
+	selectedFeed := m.GetCurrentSource()
+	if selectedFeed != nil {
+		m.filterByFeed(selectedFeed)
+		m.currentView = articlesView
+		m.selectedArticle = 0
+		m.searchQuery = ""
+		return m.SetStatusMessage("Filtered by: " + selectedFeed.Title)
+	}
+
+	if search := m.GetCurrentSavedSearch(); search != nil {
+		return m.ApplySavedSearch(*search)
+	}
+	return nil
+}
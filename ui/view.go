@@ -6,48 +6,8 @@ import (
 	"time"
 
 	lg "github.com/charmbracelet/lipgloss"
-)
-
-var (
-	activeColor   = lg.Color("111")
-	inactiveColor = lg.Color("240")
-	unreadColor   = lg.Color("75")
-	titleColor    = lg.Color("108")
-	accentColor   = lg.Color("103")
-
-	articleTitleStyle = lg.NewStyle().
-				Foreground(titleColor).
-				Bold(true)
-
-	articleTitleReadStyle = lg.NewStyle().
-				Foreground(inactiveColor)
-
-	selectedStyle = lg.NewStyle().
-			Foreground(activeColor).
-			Bold(true)
-
-	dimStyle = lg.NewStyle().
-			Foreground(inactiveColor)
-
-	descriptionStyle = lg.NewStyle().
-				Foreground(lg.Color("252"))
-
-	sourceStyle = lg.NewStyle().
-			Foreground(accentColor).
-			Italic(true)
 
-	headerStyle = lg.NewStyle().
-			Foreground(titleColor).
-			Bold(true).
-			Padding(0, 1)
-
-	statusStyle = lg.NewStyle().
-			Foreground(lg.Color("205")).
-			Padding(0, 1)
-
-	unreadDotStyle = lg.NewStyle().
-			Foreground(unreadColor).
-			Bold(true)
+	"ohnurr/rss"
 )
 
 func formatPublishDate(published time.Time) string {
@@ -96,12 +56,30 @@ func (m Model) View() string {
 		)
 	}
 
-	if len(m.feeds) == 0 {
-		return lg.Place(
-			m.width, m.height,
+	if len(m.feeds) == 0 && m.currentView != sourcesView {
+		// feeds are added from sources view, which is shown as usual
+		add := "Add feeds with: ohnurr add <url>"
+		if keys := hintKeys(m.keys.AddFeed); keys != "" && hintKeys(m.keys.Sources) != "" {
+			add = fmt.Sprintf("Press '%s' then '%s' to add a feed, or run: ohnurr add <url>", hintKeys(m.keys.Sources), keys)
+		}
+		empty := lg.Place(
+			m.width, m.height-1,
 			lg.Center, lg.Center,
-			"No feeds loaded\nAdd feeds with: ohnurr add <url>",
+			"No feeds loaded\n"+add,
 		)
+		return lg.JoinVertical(lg.Left, empty, m.renderStatusBar())
+	}
+
+	if m.showHelp {
+		return lg.JoinVertical(lg.Left, m.renderHelp(), m.renderStatusBar())
+	}
+
+	if m.detailsFeed() != nil {
+		return lg.JoinVertical(lg.Left, m.renderFeedDetails(), m.renderStatusBar())
+	}
+
+	if m.splitLayout() {
+		return lg.JoinVertical(lg.Left, m.renderSplitView(), m.renderStatusBar())
 	}
 
 	var content string
@@ -113,6 +91,10 @@ func (m Model) View() string {
 	case articleView:
 		content = m.renderArticleView()
 	}
+	if m.currentView != articleView {
+		// remove images left over from the reader
+		content = m.imageMode.Clear() + content
+	}
 
 	statusBar := m.renderStatusBar()
 
@@ -120,13 +102,27 @@ func (m Model) View() string {
 }
 
 func (m Model) renderArticlesView() string {
-	var lines []string
+	lines, _ := m.articleListLines()
+	return strings.Join(lines, "\n")
+}
+
+// returns the lines of the article list and, for each line, the index of
+// the article it belongs to (-1 for anything else), to find what was clicked
+func (m Model) articleListLines() (lines []string, owners []int) {
+	add := func(line string, owner int) {
+		lines = append(lines, line)
+		owners = append(owners, owner)
+	}
 
 	// header
-	headerText := "📰 Articles"
+	icon, title := "📰", "Articles"
+	if m.podcastMode {
+		icon, title = "🎧", "Podcasts"
+	}
 	if m.filteredFeed != nil {
-		headerText = fmt.Sprintf("📰 %s", m.filteredFeed.Title)
+		title = m.filteredFeed.Title
 	}
+	headerText := fmt.Sprintf("%s %s", icon, title)
 	if m.searchInputTrap || m.searchQuery != "" {
 		headerText += " " + dimStyle.Render(fmt.Sprintf("[search: %s", m.searchQuery))
 		if m.searchInputTrap {
@@ -135,8 +131,8 @@ func (m Model) renderArticlesView() string {
 
 		headerText += dimStyle.Render("]")
 	}
-	lines = append(lines, headerStyle.Render(headerText))
-	lines = append(lines, "")
+	add(headerStyle.Render(headerText), -1)
+	add("", -1)
 
 	// calc available height for articles
 	// reserve space for header (2 lines) and status bar (1 line)
@@ -146,9 +142,9 @@ func (m Model) renderArticlesView() string {
 
 	if len(visibleArticles) == 0 {
 		if m.searchQuery != "" {
-			lines = append(lines, dimStyle.Render("No articles match your search"))
+			add(dimStyle.Render("No articles match your search"), -1)
 		} else {
-			lines = append(lines, dimStyle.Render("No articles available"))
+			add(dimStyle.Render("No articles available"), -1)
 		}
 	} else {
 		linesPerArticle := 4
@@ -177,9 +173,15 @@ func (m Model) renderArticlesView() string {
 				indicator = dimStyle.Render("○")
 			}
 
+			// articles that arrived with the last refresh, until they're read
+			badge := ""
+			if !isRead && m.isNewArticle(article) {
+				badge = unreadDotStyle.Render(" new")
+			}
+
 			titleText := article.Title
-			if len(titleText) > m.width-6 {
-				titleText = titleText[:m.width-9] + "..."
+			if len(titleText) > m.width-6-lg.Width(badge) {
+				titleText = titleText[:max(m.width-9-lg.Width(badge), 0)] + "..."
 			}
 
 			if isSelected {
@@ -197,8 +199,9 @@ func (m Model) renderArticlesView() string {
 					titleLine += titleText
 				}
 			}
+			titleLine += badge
 
-			lines = append(lines, titleLine)
+			add(titleLine, i)
 			lineCount++
 
 			// description
@@ -212,7 +215,7 @@ func (m Model) renderArticlesView() string {
 				// skip description if it's too short
 				if (len(strings.Split(desc, " "))) > 1 {
 					descLine := "    " + descriptionStyle.Render(desc)
-					lines = append(lines, descLine)
+					add(descLine, i)
 					lineCount++
 				}
 			}
@@ -221,34 +224,59 @@ func (m Model) renderArticlesView() string {
 			if lineCount < availableHeight-2 {
 				dateStr := formatPublishDate(article.Published)
 				sourceLine := "    " + sourceStyle.Render("from "+item.feedTitle) + dimStyle.Render(" · "+dateStr)
-				lines = append(lines, sourceLine)
+				if info := m.episodeInfo(article); info != "" {
+					sourceLine += dimStyle.Render(" · " + info)
+				}
+				add(sourceLine, i)
 				lineCount++
 			}
 
 			// blank lines between articles
 			if lineCount < availableHeight-2 && i < len(visibleArticles)-1 {
-				lines = append(lines, "")
+				add("", -1)
 				lineCount++
 			}
 		}
 	}
 
-	return strings.Join(lines, "\n")
+	return lines, owners
 }
 
 func (m Model) renderSourcesView() string {
-	var lines []string
+	lines, _ := m.sourceListLines()
+	return strings.Join(lines, "\n")
+}
+
+// returns the lines of the sources view and, for each line, the index of
+// the source it belongs to (-1 for anything else)
+func (m Model) sourceListLines() (lines []string, owners []int) {
+	add := func(line string, owner int) {
+		lines = append(lines, line)
+		owners = append(owners, owner)
+	}
 
 	// header
-	lines = append(lines, headerStyle.Render("📚 Sources"))
-	lines = append(lines, "")
+	add(headerStyle.Render("📚 Sources"), -1)
+	add("", -1)
 
-	if m.filteredFeed != nil {
-		lines = append(lines, dimStyle.Render("Press 'a' to show all feeds"))
-		lines = append(lines, "")
+	if m.filteredFeed != nil || m.searchQuery != "" {
+		if keys := hintKeys(m.keys.ShowAll); keys != "" {
+			add(dimStyle.Render("Press '"+keys+"' to show all feeds"), -1)
+		}
+		add("", -1)
 	}
 
+	folder := ""
 	for i, feed := range m.feeds {
+		// feeds are sorted by folder, each starts under its name
+		if f := m.folderKey(feed); f != folder {
+			folder = f
+			if i > 0 {
+				add("", -1)
+			}
+			add(articleTitleStyle.Render("📁 "+m.config.FeedMeta[feed.URL].Folder), -1)
+		}
+
 		unreadCount := m.GetUnreadCount(feed)
 		var line string
 
@@ -279,152 +307,277 @@ func (m Model) renderSourcesView() string {
 			line = "  " + line
 		}
 
-		lines = append(lines, line)
+		add(line, i)
 	}
 
-	return strings.Join(lines, "\n")
-}
+	if len(m.config.Searches) > 0 {
+		add("", -1)
+		add(headerStyle.Render("🔎 Saved searches"), -1)
+		add("", -1)
 
-func wrapLineWithIndent(line string, maxWidth int, leftMargin int) []string {
-	// empty lines
-	if strings.TrimSpace(line) == "" {
-		return []string{""}
-	}
+		for i, search := range m.config.Searches {
+			line := search.Name + dimStyle.Render(" ["+search.Query+"]")
+			if unreadCount := m.GetSavedSearchUnreadCount(search); unreadCount > 0 {
+				line += unreadDotStyle.Render(fmt.Sprintf(" (%d unread)", unreadCount))
+			}
 
-	indent := strings.Repeat(" ", leftMargin)
+			if lg.Width(line) > m.width-6 {
+				line = search.Name
+				if len(line) > m.width-9 {
+					line = line[:m.width-9] + "..."
+				}
+			}
 
-	// dont wrap lines with ANSI escape codes (headers, code blocks)
-	if strings.Contains(line, "\x1b[") {
-		return []string{indent + line}
-	}
+			if len(m.feeds)+i == m.selectedSource {
+				line = selectedStyle.Render("▶ ") + line
+			} else {
+				line = "  " + line
+			}
 
-	var wrapped []string
-	words := strings.Fields(line)
-	if len(words) == 0 {
-		return []string{""}
+			add(line, len(m.feeds)+i)
+		}
 	}
 
-	currentLine := ""
-	for _, word := range words {
-		testLine := currentLine
-		if testLine != "" {
-			testLine += " "
-		}
-		testLine += word
+	return lines, owners
+}
 
-		if len(testLine) <= maxWidth {
-			currentLine = testLine
-		} else {
-			if currentLine != "" {
-				wrapped = append(wrapped, indent+currentLine)
-			}
-			currentLine = word
-		}
+// returns the column width articles are wrapped to, the preferred width
+// from settings unless the terminal is too narrow for it
+func (m Model) readerWidth() int {
+	width, _ := m.readerSize()
+	readableWidth := m.config.Settings.ReaderWidth
+	if width < readableWidth+20 {
+		// for narrow screens use 90% of width
+		readableWidth = min(readableWidth, int(float64(width)*0.9))
 	}
-
-	if currentLine != "" {
-		wrapped = append(wrapped, indent+currentLine)
+	if readableWidth < 20 {
+		readableWidth = 20 // min width
 	}
-
-	return wrapped
+	return readableWidth
 }
 
 func (m Model) renderArticleView() string {
-	var header []string
-
 	// add top padding
-	header = append(header, "")
-
-	a := m.GetCurrentArticle()
+	header := []string{""}
 
+	// nothing is drawn over images from the previous frame, clear them
 	if m.loadingArticle {
-		header = append(header, dimStyle.Render("Loading article..."))
+		header = append(header, m.imageMode.Clear()+dimStyle.Render("Loading article..."))
 		return strings.Join(header, "\n")
 	}
 
-	if m.cachedArticleURL != a.Link || m.cachedArticleContent == "" {
-		header = append(header, dimStyle.Render("Article not loaded. Press Esc and Enter to reload."))
+	articleContent := m.readerContent()
+	if articleContent == nil || articleContent.Text == "" {
+		header = append(header, m.imageMode.Clear()+dimStyle.Render("Article not loaded. Go back and open it again to reload."))
 		return strings.Join(header, "\n")
 	}
 
-	readableWidth := 80
-	if m.width < 100 {
-		// for narrow screens use 90% of width
-		readableWidth = int(float64(m.width) * 0.9)
+	header = m.articleHeader()
+	lineCount, availableHeight := m.articlePage()
+	startLine := min(max(m.articleScroll, 0), m.maxArticleScroll())
+	endLine := min(startLine+availableHeight, lineCount)
+
+	// content is already wrapped to the reader width, just indent it
+	indent := strings.Repeat(" ", m.readerMargin())
+	visibleContent := articleContent.VisibleLines(startLine, endLine)
+	for i, line := range visibleContent {
+		if line != "" {
+			visibleContent[i] = indent + line
+		}
+	}
+
+	// images from the previous frame are cleared whenever the content moves
+	if len(visibleContent) > 0 {
+		visibleContent[0] = m.imageMode.Clear() + visibleContent[0]
 	}
-	if readableWidth < 40 {
-		readableWidth = 40 // min width
+
+	// combine header and content
+	result := strings.Join(header, "\n")
+	if len(visibleContent) > 0 {
+		result += "\n" + strings.Join(visibleContent, "\n")
 	}
 
-	// calc left margin
-	leftMargin := max((m.width-readableWidth)/2, 2)
+	return result
+}
+
+// returns the left margin that centers the reader
+func (m Model) readerMargin() int {
+	width, _ := m.readerSize()
+	return max((width-m.readerWidth())/2, 2)
+}
+
+// returns the lines above the article content: padding, title and metadata
+func (m Model) articleHeader() []string {
+	header := []string{""}
+
+	width, _ := m.readerSize()
+	readableWidth := m.readerWidth()
+	leftMargin := m.readerMargin()
+	_, title := m.readerTarget()
 
 	// title
-	titleLine := headerStyle.Render(a.Title)
+	titleLine := headerStyle.Render(title)
 	titleWidth := lg.Width(titleLine)
-	titlePadding := (m.width - titleWidth) / 2
+	titlePadding := (width - titleWidth) / 2
 	if titlePadding > 0 {
 		header = append(header, strings.Repeat(" ", titlePadding)+titleLine)
 	} else {
 		header = append(header, strings.Repeat(" ", leftMargin)+titleLine)
 	}
-	header = append(header, "")
+	if len(m.linkHistory) > 0 {
+		// reading a link from the article
+		url, _ := m.readerTarget()
+		header = append(header, strings.Repeat(" ", leftMargin)+dimStyle.Render(truncate(url, readableWidth)))
+	} else if a := m.GetCurrentArticle(); a != nil {
+		for _, line := range renderArticleMeta(a, readableWidth) {
+			header = append(header, strings.Repeat(" ", leftMargin)+line)
+		}
+	}
+	return append(header, "")
+}
 
-	// split content into lines and wrap
-	rawLines := strings.Split(m.cachedArticleContent, "\n")
-	var wrappedLines []string
-	for _, line := range rawLines {
-		wrapped := wrapLineWithIndent(line, readableWidth, leftMargin)
-		wrappedLines = append(wrappedLines, wrapped...)
+// renders author, dates, tags, attachments and links shown under the article title
+func renderArticleMeta(a *rss.Article, width int) []string {
+	var lines []string
+
+	byline := ""
+	if len(a.Authors) > 0 {
+		byline = "by " + strings.Join(a.Authors, ", ") + " · "
+	}
+	byline += a.FeedTitle
+	if !a.Published.IsZero() {
+		byline += " · " + formatPublishDate(a.Published)
 	}
+	if !a.Updated.IsZero() && a.Updated.After(a.Published.Add(time.Minute)) {
+		byline += " (updated " + formatPublishDate(a.Updated) + ")"
+	}
+	lines = append(lines, sourceStyle.Render(truncate(byline, width)))
 
-	// calcl height for content
-	headerHeight := len(header)
-	availableHeight := m.height - headerHeight - 1
+	if len(a.Categories) > 0 {
+		lines = append(lines, dimStyle.Render(truncate("tags: "+strings.Join(a.Categories, ", "), width)))
+	}
 
-	// clamp scroll
-	scrollPos := m.articleScroll
-	maxScroll := max(len(wrappedLines)-availableHeight, 0)
-	if scrollPos > maxScroll {
-		scrollPos = maxScroll
+	for _, e := range a.Enclosures {
+		label := e.Type
+		if label == "" {
+			label = "attachment"
+		}
+		if e.Length > 0 {
+			label += ", " + formatBytes(e.Length)
+		}
+		lines = append(lines, dimStyle.Render(truncate("📎 "+label+" "+e.URL, width)))
 	}
-	if scrollPos < 0 {
-		scrollPos = 0
+
+	if a.Image != "" {
+		lines = append(lines, dimStyle.Render(truncate("🖼 "+a.Image, width)))
 	}
 
-	startLine := scrollPos
-	endLine := min(startLine+availableHeight, len(wrappedLines))
-	visibleContent := wrappedLines[startLine:endLine]
+	if a.Comments != "" {
+		lines = append(lines, dimStyle.Render(truncate("💬 "+a.Comments, width)))
+	}
 
-	// combine header and content
-	result := strings.Join(header, "\n")
-	if len(visibleContent) > 0 {
-		result += "\n" + strings.Join(visibleContent, "\n")
+	return lines
+}
+
+// shortens s to fit in width columns
+func truncate(s string, width int) string {
+	if width < 4 || lg.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lg.Width(string(runes)) > width-3 {
+		runes = runes[:len(runes)-1]
 	}
+	return string(runes) + "..."
+}
 
-	return result
+// formats a byte count as a human readable size
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
 }
 
 func (m Model) renderStatusBar() string {
+	// long help text would otherwise widen the panes of the split layout
+	style := statusStyle.MaxWidth(m.width)
+	if m.prompt != noPrompt {
+		return style.Render(m.renderPrompt())
+	}
+
+	status := m.statusText()
+	if m.currentView == articleView && !m.showHelp {
+		if percent := m.scrollPercent(); percent != "" {
+			status = percent + "  " + status
+		}
+	}
+	return style.Render(status)
+}
+
+func (m Model) statusText() string {
 	if m.statusMessage != "" {
-		return statusStyle.Render(m.statusMessage)
+		return m.statusMessage
+	}
+
+	if m.currentView == articleView && m.selectedLink > 0 {
+		return dimStyle.Render(m.linkStatus())
+	}
+
+	if len(m.downloads) > 0 {
+		return m.downloadsStatus()
 	}
 
-	return statusStyle.Render(m.getHelpText())
+	if m.splitLayout() {
+		if panes := hint("panes", m.keys.FocusLeft, m.keys.FocusRight); panes != "" {
+			return dimStyle.Render(panes+" | ") + m.getHelpText()
+		}
+	}
+	return m.getHelpText()
 }
 
 func (m Model) getHelpText() string {
 	if m.searchInputTrap {
 		return dimStyle.Render("Type to search | Enter: apply | Esc: cancel")
 	}
+	if m.showHelp {
+		return dimStyle.Render(hints(hint("scroll", m.keys.Up, m.keys.Down), "Esc: close", hint("quit", m.keys.Quit)))
+	}
 
+	k := m.keys
 	switch m.currentView {
 	case articlesView:
-		return dimStyle.Render("/: search | ↑↓/jk: nav | o: open | m: toggle-read | s: sources | r: refresh | q: quit")
+		if m.searchQuery != "" {
+			return dimStyle.Render(hints(hint("save search", k.SaveSearch), hint("clear search", k.ClearSearch), hint("nav", k.Up, k.Down),
+				hint("open", k.OpenBrowser), hint("toggle-read", k.ToggleRead), hint("sources", k.Sources), hint("help", k.Help), hint("quit", k.Quit)))
+		}
+		if m.podcastMode {
+			return dimStyle.Render(hints(hint("play", k.Play), hint("download", k.Download), hint("all articles", k.Podcasts), hint("nav", k.Up, k.Down),
+				hint("show notes", k.Open), hint("sources", k.Sources), hint("help", k.Help), hint("quit", k.Quit)))
+		}
+		return dimStyle.Render(hints(hint("search", k.Search), hint("nav", k.Up, k.Down), hint("open", k.OpenBrowser), hint("toggle-read", k.ToggleRead),
+			hint("podcasts", k.Podcasts), hint("sources", k.Sources), hint("refresh", k.Refresh), hint("help", k.Help), hint("quit", k.Quit)))
 	case articleView:
-		return dimStyle.Render("↑↓/jk: scroll | PgDn/PgUp: page | g/G: top/bottom | o: open | Esc: back | q: quit")
+		links := "1-9: links"
+		if keys := hintKeys(k.NextLink); keys != "" {
+			links = keys + "/" + links
+		}
+		jump := ""
+		if keys := hintKeys(k.JumpPercent); keys != "" {
+			jump = "N" + keys + ": jump"
+		}
+		return dimStyle.Render(hints(hint("scroll", k.Up, k.Down), hint("page", k.PageDown, k.PageUp), hint("half page", k.HalfPageDown, k.HalfPageUp),
+			hint("top/bottom", k.Top, k.Bottom), jump, hint("next/prev", k.NextArticle, k.PrevArticle), hint("next unread", k.NextUnread), links,
+			hint("open", k.OpenBrowser), hint("export", k.Export), hint("back", k.Back), hint("help", k.Help), hint("quit", k.Quit)))
 	case sourcesView:
-		return dimStyle.Render("s: back to articles | ↑↓/jk: navigate | enter: filter by source | a: show all | q: quit")
+		return dimStyle.Render(hints(hint("back to articles", k.Sources), hint("navigate", k.Up, k.Down), hint("filter by source", k.Open),
+			hint("details", k.FeedDetails), hint("add", k.AddFeed), hint("remove", k.Delete), hint("rename", k.RenameFeed), hint("move", k.MoveFeed), hint("show all", k.ShowAll), hint("help", k.Help), hint("quit", k.Quit)))
 	}
 	return ""
 }